@@ -6,6 +6,7 @@ import (
 	"context"
 	"go.uber.org/zap"
 	"log"
+	"os"
 	"os/signal"
 	"syscall"
 )
@@ -13,13 +14,33 @@ import (
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
-	
+
 	logger, err := logger.New()
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer logger.Sync()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if len(os.Args) != 3 {
+			logger.Fatal("usage: app migrate up|down|status|redo")
+		}
+		if err := app.Migrate(ctx, logger, os.Args[2]); err != nil {
+			logger.Fatal("Error running migrations", zap.Error(err))
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "rates" {
+		if len(os.Args) != 3 {
+			logger.Fatal("usage: app rates <file.json|file.csv>")
+		}
+		if err := app.LoadExchangeRates(ctx, logger, os.Args[2]); err != nil {
+			logger.Fatal("Error loading exchange rates", zap.Error(err))
+		}
+		return
+	}
+
 	if err := app.Run(ctx, logger); err != nil {
 		logger.Fatal("Error starting app", zap.Error(err))
 	}
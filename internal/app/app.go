@@ -1,16 +1,38 @@
 package app
 
 import (
+	"cart-api/internal/auth"
 	"cart-api/internal/config"
+	"cart-api/internal/exchange"
+	"cart-api/internal/metrics"
+	"cart-api/internal/migrate"
+	"cart-api/internal/pricing"
 	"cart-api/internal/repository/Cart"
+	"cart-api/internal/repository/Coupon"
+	"cart-api/internal/repository/Discount"
+	"cart-api/internal/repository/Exchange"
+	"cart-api/internal/repository/Idempotency"
+	"cart-api/internal/repository/Inventory"
+	"cart-api/internal/repository/Order"
+	"cart-api/internal/repository/Product"
+	"cart-api/internal/repository/Tax"
 	"cart-api/internal/services"
+	"cart-api/internal/shipping"
+	"cart-api/internal/tracing"
+	"cart-api/internal/transport/middleware"
 	"cart-api/internal/transport/rest"
+	"cart-api/internal/transport/rpc"
+	"cart-api/internal/worker"
 	"cart-api/pkg/database/postgres"
+	"cart-api/pkg/money"
 	"context"
 	"errors"
 	"fmt"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"net"
 	"net/http"
+	"os"
 	"time"
 )
 
@@ -19,6 +41,10 @@ func Run(ctx context.Context, logger *zap.Logger) error {
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
+	// Only serving needs the token key; migrations and rate imports do not.
+	if cfg.JWTSecret == "" {
+		return fmt.Errorf("load config: JWT_SECRET must be set")
+	}
 
 	db, err := postgres.New(&cfg.Postgres)
 	if err != nil {
@@ -26,24 +52,161 @@ func Run(ctx context.Context, logger *zap.Logger) error {
 	}
 	defer db.Close()
 
-	cartRepo := Cart.New(db)
-	cartService := services.NewCartService(cartRepo)
+	migrator, err := migrate.New(db, logger)
+	if err != nil {
+		return err
+	}
+	if cfg.MigrateOnStart {
+		if err = migrator.Up(ctx); err != nil {
+			return err
+		}
+	}
+
+	rounding, err := money.ParseRoundingMode(cfg.RoundingMode)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	strategy, err := pricing.ParseStrategy(cfg.DiscountStrategy)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	taxMode, err := pricing.ParseTaxMode(cfg.TaxMode)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	shippingMethods, err := shipping.Parse(cfg.ShippingMethods)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	limits := services.Limits{
+		MaxDistinctProducts: cfg.Limits.MaxDistinctProducts,
+		MaxUnits:            cfg.Limits.MaxUnits,
+		MaxCartValue:        cfg.Limits.MaxCartValue,
+		MaxItemPrice:        cfg.Limits.MaxItemPrice,
+		MaxNameLength:       cfg.Limits.MaxNameLength,
+		NamePattern:         cfg.Limits.NamePattern,
+	}
+	if err = limits.Validate(); err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("set up tracing: %w", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Error("failed to flush traces", zap.Error(err))
+		}
+	}()
+
+	appMetrics := metrics.New()
+	appMetrics.RegisterDB(db.DB, "postgres")
+
+	exchangeRepo := Exchange.New(db)
+	if cfg.ExchangeRates != "" {
+		if err = loadExchangeRates(ctx, exchangeRepo, cfg.ExchangeRates, logger); err != nil {
+			return err
+		}
+	}
+
+	cartRepo := Cart.New(db, Cart.WithQueryObserver(appMetrics.Repository("cart")))
+	discountRepo := Discount.New(db)
+	couponRepo := Coupon.New(db)
+	orderRepo := Order.New(db)
+	idempotencyRepo := Idempotency.New(db)
+	productRepo := Product.New(db)
+	inventoryRepo := Inventory.New(db)
+	taxRepo := Tax.New(db)
+	cartService := services.NewCartService(cartRepo,
+		services.WithRounding(rounding),
+		services.WithDiscountRules(discountRepo, strategy),
+		services.WithCoupons(couponRepo),
+		services.WithOrders(orderRepo),
+		services.WithCatalog(productRepo),
+		services.WithInventory(inventoryRepo, cfg.ReservationTTL),
+		services.WithTax(taxRepo, taxMode, cfg.TaxRegion),
+		services.WithShipping(shippingMethods),
+		services.WithExchangeRates(exchangeRepo),
+		services.WithLimits(limits),
+		services.WithMetrics(appMetrics),
+	)
+	catalogService := services.NewCatalogService(productRepo, inventoryRepo)
+	sweeper := worker.NewSweeper(cartRepo, worker.SweeperConfig{
+		AbandonAfter: cfg.CartAbandonAfter,
+		TTL:          cfg.CartTTL,
+		Interval:     cfg.SweepInterval,
+		BatchSize:    cfg.SweepBatchSize,
+	}, logger, worker.WithIdempotencyKeys(idempotencyRepo), worker.WithReservations(inventoryRepo))
+	// The sweeper gets its own context so that returning early, e.g. when the
+	// gRPC port is taken, stops it instead of waiting on it forever.
+	sweeperCtx, stopSweeper := context.WithCancel(ctx)
+	sweeperDone := make(chan struct{})
+	go func() {
+		defer close(sweeperDone)
+		sweeper.Run(sweeperCtx)
+	}()
+	defer func() {
+		stopSweeper()
+		<-sweeperDone
+	}()
+
 	mux := http.NewServeMux()
 	cartHandler := rest.NewCartHandler(cartService, logger)
+	productHandler := rest.NewProductHandler(catalogService, logger)
+	healthHandler := rest.NewHealthHandler(logger,
+		rest.HealthCheck{Name: "database", Check: db.PingContext},
+		rest.HealthCheck{Name: "migrations", Check: migrator.CheckVersion},
+	)
 
 	logger.Info("starting server", zap.String("host", "localhost"), zap.String("port", "3000"))
 
-	mux.HandleFunc("DELETE /carts/{cart_id}/items/{item_id}", cartHandler.DeleteItem)
-	mux.HandleFunc("POST /carts", cartHandler.PostCart)
-	mux.HandleFunc("POST /carts/{cart_id}/items", cartHandler.PostItem)
-	mux.HandleFunc("GET /carts/{cart_id}", cartHandler.GetItems)
-	mux.HandleFunc("GET /carts/{cart_id}/price", cartHandler.GetPrice)
+	verifier := auth.NewVerifier([]byte(cfg.JWTSecret), cfg.JWTIssuer)
+	authenticate := middleware.Authenticate(verifier, logger)
+	idempotent := middleware.Idempotency(idempotencyRepo, cfg.IdempotencyTTL, logger)
+	for _, rt := range routes(routeHandlers{
+		cart:         cartHandler,
+		product:      productHandler,
+		health:       healthHandler,
+		metrics:      appMetrics.Handler(),
+		authenticate: authenticate,
+		idempotent:   idempotent,
+	}) {
+		mux.Handle(rt.pattern, rt.handler)
+	}
+
+	handler := middleware.Chain(mux,
+		middleware.RequestID(),
+		middleware.Tracing(mux),
+		middleware.AccessLog(logger, mux),
+		middleware.Metrics(appMetrics, mux),
+		middleware.Recover(logger),
+	)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.HTTPPort),
-		Handler: mux,
+		Handler: handler,
 	}
 
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
+	if err != nil {
+		return fmt.Errorf("listen for grpc: %w", err)
+	}
+	grpcServer := rpc.NewServer(rpc.NewCartServer(cartService, logger), verifier, logger)
+
+	go func() {
+		logger.Info("starting grpc server", zap.String("port", cfg.GRPCPort))
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Fatal("grpc serve failed", zap.Error(err))
+		}
+	}()
+
 	go func() {
 		logger.Info("starting server", zap.String("port", cfg.HTTPPort))
 		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -53,12 +216,25 @@ func Run(ctx context.Context, logger *zap.Logger) error {
 	}()
 	<-ctx.Done()
 
-	logger.Info("shutting down server...")
+	logger.Info("shutting down server...", zap.Duration("drain_delay", cfg.DrainDelay))
+
+	// Fail readiness first and give load balancers time to stop routing new
+	// requests here before the listener is closed.
+	healthHandler.Drain()
+	time.Sleep(cfg.DrainDelay)
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err = server.Shutdown(shutdownCtx); err != nil {
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		stopGRPC(shutdownCtx, grpcServer)
+	}()
+
+	err = server.Shutdown(shutdownCtx)
+	<-grpcStopped
+	if err != nil {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
@@ -66,3 +242,67 @@ func Run(ctx context.Context, logger *zap.Logger) error {
 
 	return nil
 }
+
+// stopGRPC waits for in-flight calls like GracefulStop, but cancels those
+// still running once ctx expires.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+		<-stopped
+	}
+}
+
+func Migrate(ctx context.Context, logger *zap.Logger, command string) error {
+	cfg, err := config.New()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	db, err := postgres.New(&cfg.Postgres)
+	if err != nil {
+		return fmt.Errorf("connect to postgres: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, logger)
+	if err != nil {
+		return err
+	}
+	return migrator.Run(ctx, command, os.Stdout)
+}
+
+// LoadExchangeRates imports the rates of a JSON or CSV file into the
+// exchange_rates table, replacing rates already stored for the same pairs.
+func LoadExchangeRates(ctx context.Context, logger *zap.Logger, path string) error {
+	cfg, err := config.New()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	db, err := postgres.New(&cfg.Postgres)
+	if err != nil {
+		return fmt.Errorf("connect to postgres: %w", err)
+	}
+	defer db.Close()
+
+	return loadExchangeRates(ctx, Exchange.New(db), path, logger)
+}
+
+func loadExchangeRates(ctx context.Context, repo *Exchange.ExchangeRepo, path string, logger *zap.Logger) error {
+	rates, err := exchange.Load(path, time.Now())
+	if err != nil {
+		return err
+	}
+	if err = repo.SaveExchangeRates(ctx, rates); err != nil {
+		return fmt.Errorf("save exchange rates: %w", err)
+	}
+	logger.Info("exchange rates loaded", zap.String("file", path), zap.Int("rates", len(rates)))
+	return nil
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"cart-api/internal/transport/openapi"
+	"cart-api/internal/transport/rest"
+	"net/http"
+)
+
+type route struct {
+	pattern string
+	handler http.Handler
+}
+
+type routeHandlers struct {
+	cart         *rest.CartHandler
+	product      *rest.ProductHandler
+	health       *rest.HealthHandler
+	metrics      http.Handler
+	authenticate func(http.Handler) http.Handler
+	idempotent   func(http.Handler) http.Handler
+}
+
+// routes lists every HTTP route of the service. Each of them must be described
+// in openapi.json; routes_test.go fails when the two drift apart.
+func routes(h routeHandlers) []route {
+	authenticate, idempotent := h.authenticate, h.idempotent
+	return []route{
+		{"DELETE /carts/{cart_id}/items/{item_id}", authenticate(http.HandlerFunc(h.cart.DeleteItem))},
+		{"POST /carts", authenticate(idempotent(http.HandlerFunc(h.cart.PostCart)))},
+		{"POST /carts/{cart_id}/items", authenticate(idempotent(http.HandlerFunc(h.cart.PostItem)))},
+		{"PATCH /carts/{cart_id}/items/{item_id}", authenticate(http.HandlerFunc(h.cart.PatchItem))},
+		{"GET /carts/{cart_id}", authenticate(http.HandlerFunc(h.cart.GetItems))},
+		{"GET /carts/{cart_id}/price", authenticate(http.HandlerFunc(h.cart.GetPrice))},
+		{"POST /carts/{cart_id}/merge", authenticate(http.HandlerFunc(h.cart.PostMerge))},
+		{"POST /carts/{cart_id}/checkout/begin", authenticate(http.HandlerFunc(h.cart.PostBeginCheckout))},
+		{"POST /carts/{cart_id}/checkout", authenticate(http.HandlerFunc(h.cart.PostCheckout))},
+		{"POST /carts/{cart_id}/checkout/cancel", authenticate(http.HandlerFunc(h.cart.PostCancelCheckout))},
+		{"POST /carts/{cart_id}/coupons", authenticate(http.HandlerFunc(h.cart.PostCoupon))},
+		{"DELETE /carts/{cart_id}/coupons/{code}", authenticate(http.HandlerFunc(h.cart.DeleteCoupon))},
+		{"GET /carts/{cart_id}/shipping-options", authenticate(http.HandlerFunc(h.cart.GetShippingOptions))},
+		{"PUT /carts/{cart_id}/shipping", authenticate(http.HandlerFunc(h.cart.PutShipping))},
+		{"GET /products", authenticate(http.HandlerFunc(h.product.GetProducts))},
+		{"GET /products/{sku}", authenticate(http.HandlerFunc(h.product.GetProduct))},
+		{"POST /products", authenticate(http.HandlerFunc(h.product.PostProduct))},
+		{"PUT /products/{sku}", authenticate(http.HandlerFunc(h.product.PutProduct))},
+		{"DELETE /products/{sku}", authenticate(http.HandlerFunc(h.product.DeleteProduct))},
+		{"GET /products/{sku}/stock", authenticate(http.HandlerFunc(h.product.GetStock))},
+		{"PUT /products/{sku}/stock", authenticate(http.HandlerFunc(h.product.PutStock))},
+		{"GET /metrics", h.metrics},
+		{"GET /healthz", http.HandlerFunc(h.health.GetLive)},
+		{"GET /readyz", http.HandlerFunc(h.health.GetReady)},
+		{"GET /openapi.json", openapi.Handler()},
+		{"GET /docs", openapi.DocsHandler()},
+	}
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"cart-api/internal/transport/openapi"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoutes_MatchOpenAPI fails when a route is registered without being
+// described in openapi.json, or the spec documents a route that is gone.
+func TestRoutes_MatchOpenAPI(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(openapi.Spec, &spec))
+
+	documented := map[string]bool{}
+	for path, item := range spec.Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	passthrough := func(h http.Handler) http.Handler { return h }
+	registered := map[string]bool{}
+	mux := http.NewServeMux()
+	for _, rt := range routes(routeHandlers{
+		metrics:      http.NotFoundHandler(),
+		authenticate: passthrough,
+		idempotent:   passthrough,
+	}) {
+		assert.False(t, registered[rt.pattern], "%s registered twice", rt.pattern)
+		registered[rt.pattern] = true
+		mux.Handle(rt.pattern, rt.handler)
+		assert.True(t, documented[rt.pattern], "%s is not described in openapi.json", rt.pattern)
+	}
+	for pattern := range documented {
+		assert.True(t, registered[pattern], "openapi.json describes %s, which is not registered", pattern)
+	}
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Principal identifies the caller. Guests only have a SessionID; signed-in
+// users have a UserID and, when they signed in from a guest session, its SessionID.
+type Principal struct {
+	UserID    string
+	SessionID string
+	Admin     bool
+}
+
+func (p Principal) IsGuest() bool {
+	return p.UserID == ""
+}
+
+type Claims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+	Guest     bool   `json:"guest,omitempty"`
+	Admin     bool   `json:"admin,omitempty"`
+}
+
+type Verifier struct {
+	key    []byte
+	issuer string
+}
+
+func NewVerifier(key []byte, issuer string) *Verifier {
+	return &Verifier{
+		key:    key,
+		issuer: issuer,
+	}
+}
+
+func (v *Verifier) Verify(token string) (Principal, error) {
+	if token == "" {
+		return Principal{}, ErrMissingToken
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodHS384.Alg(), jwt.SigningMethodHS512.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if v.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.issuer))
+	}
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return v.key, nil
+	}, opts...)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	if claims.Guest {
+		return Principal{SessionID: claims.Subject}, nil
+	}
+	return Principal{
+		UserID:    claims.Subject,
+		SessionID: claims.SessionID,
+		Admin:     claims.Admin,
+	}, nil
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
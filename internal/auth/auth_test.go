@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("test-secret")
+
+func sign(t *testing.T, method jwt.SigningMethod, key []byte, claims Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	registered := func(sub string, exp time.Time) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{Subject: sub, Issuer: "cart-api", ExpiresAt: jwt.NewNumericDate(exp)}
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		expected  Principal
+		expectErr error
+	}{
+		{
+			name:     "User",
+			token:    sign(t, jwt.SigningMethodHS256, testKey, Claims{RegisteredClaims: registered("user-1", now.Add(time.Hour)), SessionID: "sess-1"}),
+			expected: Principal{UserID: "user-1", SessionID: "sess-1"},
+		},
+		{
+			name:     "Guest",
+			token:    sign(t, jwt.SigningMethodHS512, testKey, Claims{RegisteredClaims: registered("sess-1", now.Add(time.Hour)), Guest: true}),
+			expected: Principal{SessionID: "sess-1"},
+		},
+		{
+			name:     "Admin",
+			token:    sign(t, jwt.SigningMethodHS256, testKey, Claims{RegisteredClaims: registered("root", now.Add(time.Hour)), Admin: true}),
+			expected: Principal{UserID: "root", Admin: true},
+		},
+		{
+			name:      "Missing",
+			expectErr: ErrMissingToken,
+		},
+		{
+			name:      "Expired",
+			token:     sign(t, jwt.SigningMethodHS256, testKey, Claims{RegisteredClaims: registered("user-1", now.Add(-time.Hour))}),
+			expectErr: ErrInvalidToken,
+		},
+		{
+			name:      "Wrong Key",
+			token:     sign(t, jwt.SigningMethodHS256, []byte("other"), Claims{RegisteredClaims: registered("user-1", now.Add(time.Hour))}),
+			expectErr: ErrInvalidToken,
+		},
+		{
+			name:      "No Expiry",
+			token:     sign(t, jwt.SigningMethodHS256, testKey, Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1", Issuer: "cart-api"}}),
+			expectErr: ErrInvalidToken,
+		},
+		{
+			name:      "No Subject",
+			token:     sign(t, jwt.SigningMethodHS256, testKey, Claims{RegisteredClaims: registered("", now.Add(time.Hour))}),
+			expectErr: ErrInvalidToken,
+		},
+		{
+			name:      "Wrong Issuer",
+			token:     sign(t, jwt.SigningMethodHS256, testKey, Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1", Issuer: "other", ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))}}),
+			expectErr: ErrInvalidToken,
+		},
+	}
+
+	verifier := NewVerifier(testKey, "cart-api")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := verifier.Verify(tt.token)
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, p)
+		})
+	}
+}
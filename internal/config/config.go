@@ -1,15 +1,55 @@
 package config
 
 import (
+	"cart-api/internal/tracing"
 	"cart-api/pkg/database/postgres"
+	"cart-api/pkg/money"
 	"fmt"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 	"os"
+	"time"
 )
 
+const defaultShippingMethods = `[
+	{"method": "standard", "name": "Standard", "kind": "weight_tier", "free_over": "100.00",
+	 "tiers": [{"max_grams": 1000, "price": "4.99"}, {"max_grams": 5000, "price": "9.99"}, {"max_grams": 20000, "price": "19.99"}]},
+	{"method": "express", "name": "Express", "kind": "flat_rate", "price": "24.99"}
+]`
+
 type Config struct {
-	HTTPPort string          `mapstructure:"HTTP_PORT"`
-	Postgres postgres.Config `mapstructure:",squash"`
+	HTTPPort         string          `mapstructure:"HTTP_PORT"`
+	GRPCPort         string          `mapstructure:"GRPC_PORT"`
+	RoundingMode     string          `mapstructure:"ROUNDING_MODE"`
+	DiscountStrategy string          `mapstructure:"DISCOUNT_STRATEGY"`
+	MigrateOnStart   bool            `mapstructure:"MIGRATE_ON_START"`
+	JWTSecret        string          `mapstructure:"JWT_SECRET"`
+	JWTIssuer        string          `mapstructure:"JWT_ISSUER"`
+	CartTTL          time.Duration   `mapstructure:"CART_TTL"`
+	CartAbandonAfter time.Duration   `mapstructure:"CART_ABANDON_AFTER"`
+	SweepInterval    time.Duration   `mapstructure:"SWEEP_INTERVAL"`
+	SweepBatchSize   int             `mapstructure:"SWEEP_BATCH_SIZE"`
+	IdempotencyTTL   time.Duration   `mapstructure:"IDEMPOTENCY_TTL"`
+	ReservationTTL   time.Duration   `mapstructure:"RESERVATION_TTL"`
+	TaxMode          string          `mapstructure:"TAX_MODE"`
+	TaxRegion        string          `mapstructure:"TAX_DEFAULT_REGION"`
+	ShippingMethods  string          `mapstructure:"SHIPPING_METHODS"`
+	ExchangeRates    string          `mapstructure:"EXCHANGE_RATES_FILE"`
+	DrainDelay       time.Duration   `mapstructure:"SHUTDOWN_DRAIN_DELAY"`
+	Limits           LimitsConfig    `mapstructure:",squash"`
+	Tracing          tracing.Config  `mapstructure:",squash"`
+	Postgres         postgres.Config `mapstructure:",squash"`
+}
+
+// LimitsConfig holds the cart limits as read from the environment; app turns
+// them into services.Limits and validates them.
+type LimitsConfig struct {
+	MaxDistinctProducts int         `mapstructure:"CART_MAX_DISTINCT_PRODUCTS"`
+	MaxUnits            int         `mapstructure:"CART_MAX_UNITS"`
+	MaxCartValue        money.Money `mapstructure:"CART_MAX_VALUE"`
+	MaxItemPrice        money.Money `mapstructure:"CART_MAX_ITEM_PRICE"`
+	MaxNameLength       int         `mapstructure:"PRODUCT_NAME_MAX_LENGTH"`
+	NamePattern         string      `mapstructure:"PRODUCT_NAME_PATTERN"`
 }
 
 func New() (*Config, error) {
@@ -17,12 +57,61 @@ func New() (*Config, error) {
 	viper.AutomaticEnv()
 
 	_ = viper.BindEnv("HTTP_PORT")
+	_ = viper.BindEnv("GRPC_PORT")
+	_ = viper.BindEnv("ROUNDING_MODE")
+	_ = viper.BindEnv("DISCOUNT_STRATEGY")
+	_ = viper.BindEnv("MIGRATE_ON_START")
+	_ = viper.BindEnv("JWT_SECRET")
+	_ = viper.BindEnv("JWT_ISSUER")
+	_ = viper.BindEnv("CART_TTL")
+	_ = viper.BindEnv("CART_ABANDON_AFTER")
+	_ = viper.BindEnv("SWEEP_INTERVAL")
+	_ = viper.BindEnv("SWEEP_BATCH_SIZE")
+	_ = viper.BindEnv("IDEMPOTENCY_TTL")
+	_ = viper.BindEnv("RESERVATION_TTL")
+	_ = viper.BindEnv("TAX_MODE")
+	_ = viper.BindEnv("TAX_DEFAULT_REGION")
+	_ = viper.BindEnv("SHIPPING_METHODS")
+	_ = viper.BindEnv("EXCHANGE_RATES_FILE")
+	_ = viper.BindEnv("SHUTDOWN_DRAIN_DELAY")
+	_ = viper.BindEnv("CART_MAX_DISTINCT_PRODUCTS")
+	_ = viper.BindEnv("CART_MAX_UNITS")
+	_ = viper.BindEnv("CART_MAX_VALUE")
+	_ = viper.BindEnv("CART_MAX_ITEM_PRICE")
+	_ = viper.BindEnv("PRODUCT_NAME_MAX_LENGTH")
+	_ = viper.BindEnv("PRODUCT_NAME_PATTERN")
+	_ = viper.BindEnv("TRACING_EXPORTER")
+	_ = viper.BindEnv("TRACING_OTLP_ENDPOINT")
+	_ = viper.BindEnv("TRACING_OTLP_INSECURE")
+	_ = viper.BindEnv("TRACING_SAMPLE_RATIO")
 	_ = viper.BindEnv("POSTGRES_HOST")
 	_ = viper.BindEnv("POSTGRES_PORT")
 	_ = viper.BindEnv("POSTGRES_USER")
 	_ = viper.BindEnv("POSTGRES_PASS")
 	_ = viper.BindEnv("POSTGRES_DB")
 
+	viper.SetDefault("GRPC_PORT", "9090")
+	viper.SetDefault("ROUNDING_MODE", "half_up")
+	viper.SetDefault("DISCOUNT_STRATEGY", "best")
+	viper.SetDefault("MIGRATE_ON_START", true)
+	viper.SetDefault("CART_TTL", "720h")
+	viper.SetDefault("CART_ABANDON_AFTER", "24h")
+	viper.SetDefault("SWEEP_INTERVAL", "10m")
+	viper.SetDefault("SWEEP_BATCH_SIZE", 500)
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("RESERVATION_TTL", "15m")
+	viper.SetDefault("TAX_MODE", "exclusive")
+	viper.SetDefault("SHIPPING_METHODS", defaultShippingMethods)
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", "5s")
+	viper.SetDefault("CART_MAX_DISTINCT_PRODUCTS", 5)
+	viper.SetDefault("CART_MAX_UNITS", 0)
+	viper.SetDefault("CART_MAX_VALUE", "0")
+	viper.SetDefault("CART_MAX_ITEM_PRICE", "0")
+	viper.SetDefault("PRODUCT_NAME_MAX_LENGTH", 255)
+	viper.SetDefault("PRODUCT_NAME_PATTERN", "")
+	viper.SetDefault("TRACING_EXPORTER", tracing.ExporterNone)
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+
 	viper.SetConfigFile(".env")
 
 	if _, err := os.Stat(".env"); err == nil {
@@ -31,9 +120,29 @@ func New() (*Config, error) {
 		}
 	} else {
 	}
-	if err := viper.Unmarshal(&cfg); err != nil {
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.TextUnmarshallerHookFunc(),
+	))
+	if err := viper.Unmarshal(&cfg, decodeHook); err != nil {
 		return nil, fmt.Errorf("cannot unmarshal config: %w", err)
 	}
+	if cfg.CartTTL <= 0 || cfg.CartAbandonAfter <= 0 || cfg.SweepInterval <= 0 || cfg.SweepBatchSize <= 0 {
+		return nil, fmt.Errorf("CART_TTL, CART_ABANDON_AFTER, SWEEP_INTERVAL and SWEEP_BATCH_SIZE must be positive")
+	}
+	if cfg.IdempotencyTTL <= 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_TTL must be positive")
+	}
+	if cfg.ReservationTTL <= 0 {
+		return nil, fmt.Errorf("RESERVATION_TTL must be positive")
+	}
+	if cfg.DrainDelay < 0 {
+		return nil, fmt.Errorf("SHUTDOWN_DRAIN_DELAY must not be negative")
+	}
+	if err := cfg.Tracing.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
@@ -0,0 +1,111 @@
+package exchange
+
+import (
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type rateRecord struct {
+	Base      string      `json:"base"`
+	Quote     string      `json:"quote"`
+	Rate      json.Number `json:"rate"`
+	UpdatedAt string      `json:"updated_at"`
+}
+
+// Load reads exchange rates from a .json or .csv file. Rates without an
+// updated_at are stamped with now.
+func Load(path string, now time.Time) ([]model.ExchangeRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open exchange rates: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReadJSON(f, now)
+	case ".csv":
+		return ReadCSV(f, now)
+	}
+	return nil, fmt.Errorf("exchange rates file %q: expected .json or .csv", path)
+}
+
+// ReadJSON reads an array of {"base", "quote", "rate", "updated_at"} objects.
+func ReadJSON(r io.Reader, now time.Time) ([]model.ExchangeRate, error) {
+	var records []rateRecord
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates: %w", err)
+	}
+	rates := make([]model.ExchangeRate, 0, len(records))
+	for i, record := range records {
+		rate, err := record.toDomain(now)
+		if err != nil {
+			return nil, fmt.Errorf("exchange rate %d: %w", i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// ReadCSV reads rows of base,quote,rate[,updated_at] after a header row.
+func ReadCSV(r io.Reader, now time.Time) ([]model.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates: %w", err)
+	}
+	var rates []model.ExchangeRate
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rates: %w", err)
+		}
+		if len(row) < 3 || len(row) > 4 {
+			return nil, fmt.Errorf("exchange rates line %d: expected base,quote,rate[,updated_at]", line)
+		}
+		record := rateRecord{Base: row[0], Quote: row[1], Rate: json.Number(row[2])}
+		if len(row) == 4 {
+			record.UpdatedAt = row[3]
+		}
+		rate, err := record.toDomain(now)
+		if err != nil {
+			return nil, fmt.Errorf("exchange rates line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+}
+
+func (r rateRecord) toDomain(now time.Time) (model.ExchangeRate, error) {
+	base := strings.ToUpper(strings.TrimSpace(r.Base))
+	quote := strings.ToUpper(strings.TrimSpace(r.Quote))
+	if !money.ValidCurrency(base) || !money.ValidCurrency(quote) {
+		return model.ExchangeRate{}, money.ErrInvalidCurrency
+	}
+	if base == quote {
+		return model.ExchangeRate{}, fmt.Errorf("%s cannot be converted into itself", base)
+	}
+	rate, err := ParseRate(r.Rate.String())
+	if err != nil {
+		return model.ExchangeRate{}, err
+	}
+	updatedAt := now
+	if s := strings.TrimSpace(r.UpdatedAt); s != "" {
+		if updatedAt, err = time.Parse(time.RFC3339, s); err != nil {
+			return model.ExchangeRate{}, fmt.Errorf("updated_at must be RFC 3339: %w", err)
+		}
+	}
+	return model.ExchangeRate{Base: base, Quote: quote, Rate: rate, UpdatedAt: updatedAt.UTC()}, nil
+}
@@ -0,0 +1,85 @@
+package exchange
+
+import (
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the denominator of model.ExchangeRate.Rate.
+const RateScale = 100_000_000
+
+const rateDigits = 8
+
+var ErrInvalidRate = errors.New("exchange rate must be a positive decimal with at most 8 fractional digits")
+
+// ParseRate parses a decimal rate such as "0.9215" into RateScale units.
+func ParseRate(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > rateDigits {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	frac += strings.Repeat("0", rateDigits-len(frac))
+	for _, c := range whole + frac {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+		}
+	}
+	rate, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return rate, nil
+}
+
+// FormatRate renders a rate in RateScale units as a decimal, e.g. 92150000 as
+// "0.9215".
+func FormatRate(rate int64) string {
+	s := fmt.Sprintf("%d.%08d", rate/RateScale, rate%RateScale)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// Invert returns the rate converting quote back into base.
+func Invert(rate model.ExchangeRate) model.ExchangeRate {
+	n := new(big.Int).Mul(big.NewInt(RateScale), big.NewInt(RateScale))
+	d := big.NewInt(rate.Rate)
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Lsh(r, 1).Cmp(d) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return model.ExchangeRate{
+		Base:      rate.Quote,
+		Quote:     rate.Base,
+		Rate:      q.Int64(),
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+// Convert converts m, which must be in rate.Base, into rate.Quote.
+func Convert(m money.Money, rate model.ExchangeRate, mode money.RoundingMode) money.Money {
+	return m.Convert(rate.Quote, rate.Rate, RateScale, mode)
+}
+
+// Converter converts amounts into one currency. It reports false for amounts
+// it has no exchange rate for.
+type Converter func(money.Money) (money.Money, bool)
+
+// NewConverter returns a Converter into currency. Amounts already in
+// currency pass unchanged and amounts in rate.Base are converted at rate;
+// rate is nil when none is known.
+func NewConverter(currency string, rate *model.ExchangeRate, mode money.RoundingMode) Converter {
+	return func(m money.Money) (money.Money, bool) {
+		switch {
+		case m.Currency == currency:
+			return m, true
+		case rate != nil && m.Currency == rate.Base && rate.Quote == currency:
+			return Convert(m, *rate, mode), true
+		}
+		return money.Money{}, false
+	}
+}
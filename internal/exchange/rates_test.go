@@ -0,0 +1,122 @@
+package exchange
+
+import (
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  int64
+		expectErr bool
+	}{
+		{input: "0.9215", expected: 92_150_000},
+		{input: "157", expected: 15_700_000_000},
+		{input: "0.00000001", expected: 1},
+		{input: "0", expectErr: true},
+		{input: "-1.2", expectErr: true},
+		{input: "1.123456789", expectErr: true},
+		{input: "abc", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rate, err := ParseRate(tt.input)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidRate)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rate)
+			assert.Equal(t, tt.input, FormatRate(rate))
+		})
+	}
+}
+
+func TestInvertAndConvert(t *testing.T) {
+	rate := model.ExchangeRate{Base: "USD", Quote: "EUR", Rate: 80_000_000}
+
+	inverse := Invert(rate)
+	assert.Equal(t, "EUR", inverse.Base)
+	assert.Equal(t, "USD", inverse.Quote)
+	assert.Equal(t, "1.25", FormatRate(inverse.Rate))
+
+	converted := Convert(money.MustParse("19.99", "USD"), rate, money.RoundHalfUp)
+	assert.Equal(t, "15.99", converted.String())
+	assert.Equal(t, "EUR", converted.Currency)
+}
+
+func TestConverter(t *testing.T) {
+	rate := model.ExchangeRate{Base: "USD", Quote: "JPY", Rate: 15_000_000_000}
+	convert := NewConverter("JPY", &rate, money.RoundHalfUp)
+
+	converted, ok := convert(money.MustParse("10.00", "USD"))
+	require.True(t, ok)
+	assert.Equal(t, money.MustParse("1500", "JPY"), converted)
+	converted, ok = convert(money.MustParse("700", "JPY"))
+	require.True(t, ok)
+	assert.Equal(t, "700", converted.String())
+	_, ok = convert(money.MustParse("10.00", "EUR"))
+	assert.False(t, ok)
+
+	_, ok = NewConverter("JPY", nil, money.RoundHalfUp)(money.MustParse("10.00", "USD"))
+	assert.False(t, ok)
+}
+
+func TestLoad(t *testing.T) {
+	now := time.Date(2026, 4, 13, 9, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+
+	t.Run("JSON", func(t *testing.T) {
+		path := filepath.Join(dir, "rates.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[
+			{"base": "usd", "quote": "EUR", "rate": 0.92, "updated_at": "2026-04-12T16:00:00Z"},
+			{"base": "USD", "quote": "JPY", "rate": "151.3"}
+		]`), 0o600))
+
+		rates, err := Load(path, now)
+
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, model.ExchangeRate{Base: "USD", Quote: "EUR", Rate: 92_000_000, UpdatedAt: time.Date(2026, 4, 12, 16, 0, 0, 0, time.UTC)}, rates[0])
+		assert.Equal(t, now, rates[1].UpdatedAt)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		path := filepath.Join(dir, "rates.csv")
+		require.NoError(t, os.WriteFile(path, []byte("base,quote,rate,updated_at\nUSD,GBP,0.79,2026-04-12T16:00:00Z\nEUR,GBP,0.86\n"), 0o600))
+
+		rates, err := Load(path, now)
+
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, int64(79_000_000), rates[0].Rate)
+		assert.Equal(t, "EUR", rates[1].Base)
+	})
+
+	t.Run("Invalid Row", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.csv")
+		require.NoError(t, os.WriteFile(path, []byte("base,quote,rate\nUSD,USD,1\n"), 0o600))
+
+		_, err := Load(path, now)
+
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("Unknown Extension", func(t *testing.T) {
+		path := filepath.Join(dir, "rates.txt")
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		_, err := Load(path, now)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "cart_api"
+
+// Metrics owns the registry served on /metrics. It records HTTP traffic,
+// repository query latency and the business events of services.Metrics.
+type Metrics struct {
+	registry         *prometheus.Registry
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	queryDuration    *prometheus.HistogramVec
+	cartsCreated     prometheus.Counter
+	itemsAdded       prometheus.Counter
+	limitRejections  *prometheus.CounterVec
+	discountsApplied *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_query_duration_seconds",
+			Help:      "Latency of repository methods.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"repository", "method"}),
+		cartsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "carts_created_total",
+			Help:      "Carts created.",
+		}),
+		itemsAdded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cart_items_added_total",
+			Help:      "Items added to carts.",
+		}),
+		limitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cart_limit_rejections_total",
+			Help:      "Cart changes rejected by a business limit.",
+		}, []string{"limit"}),
+		discountsApplied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "discounts_applied_total",
+			Help:      "Discount rules and coupons applied to orders at checkout.",
+		}, []string{"kind"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.queryDuration,
+		m.cartsCreated,
+		m.itemsAdded,
+		m.limitRejections,
+		m.discountsApplied,
+	)
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDB exports the connection pool stats of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+func (m *Metrics) ObserveRequest(method, route string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+}
+
+// Repository returns an observer for the methods of one repository.
+func (m *Metrics) Repository(name string) *QueryObserver {
+	return &QueryObserver{durations: m.queryDuration.MustCurryWith(prometheus.Labels{"repository": name})}
+}
+
+func (m *Metrics) CartCreated() {
+	m.cartsCreated.Inc()
+}
+
+func (m *Metrics) ItemAdded() {
+	m.itemsAdded.Inc()
+}
+
+func (m *Metrics) LimitRejected(limit string) {
+	m.limitRejections.WithLabelValues(limit).Inc()
+}
+
+func (m *Metrics) DiscountApplied(kind string) {
+	m.discountsApplied.WithLabelValues(kind).Inc()
+}
+
+type QueryObserver struct {
+	durations prometheus.ObserverVec
+}
+
+func (o *QueryObserver) ObserveQuery(method string, d time.Duration) {
+	o.durations.WithLabelValues(method).Observe(d.Seconds())
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+
+	m.ObserveRequest(http.MethodGet, "GET /carts/{cart_id}", http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "GET /carts/{cart_id}", http.StatusOK, 30*time.Millisecond)
+	m.Repository("cart").ObserveQuery("GetCart", time.Millisecond)
+	m.CartCreated()
+	m.ItemAdded()
+	m.LimitRejected("max_units")
+	m.DiscountApplied("coupon")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "GET /carts/{cart_id}", "200")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.queryDuration, "cart_api_repository_query_duration_seconds"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.cartsCreated))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.limitRejections.WithLabelValues("max_units")))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := w.Body.String()
+	assert.Equal(t, http.StatusOK, w.Code)
+	for _, name := range []string{
+		`cart_api_http_requests_total{method="GET",route="GET /carts/{cart_id}",status="200"} 2`,
+		`cart_api_http_request_duration_seconds_count{method="GET",route="GET /carts/{cart_id}",status="200"} 2`,
+		`cart_api_repository_query_duration_seconds_count{method="GetCart",repository="cart"} 1`,
+		`cart_api_cart_items_added_total 1`,
+		`cart_api_discounts_applied_total{kind="coupon"} 1`,
+		`go_goroutines`,
+	} {
+		assert.True(t, strings.Contains(body, name), "missing %s", name)
+	}
+}
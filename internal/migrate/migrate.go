@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"cart-api/migrations"
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"go.uber.org/zap"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	CommandUp     = "up"
+	CommandDown   = "down"
+	CommandStatus = "status"
+	CommandRedo   = "redo"
+)
+
+type Migrator struct {
+	provider *goose.Provider
+	logger   *zap.Logger
+}
+
+func New(db *sqlx.DB, logger *zap.Logger) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("create migration locker: %w", err)
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, db.DB, migrations.FS,
+		goose.WithSessionLocker(locker),
+		goose.WithLogger(goose.NopLogger()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create migration provider: %w", err)
+	}
+	return &Migrator{
+		provider: provider,
+		logger:   logger,
+	}, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) error {
+	results, err := m.provider.Up(ctx)
+	m.logResults(results...)
+	if err != nil {
+		return fmt.Errorf("migrate up: %w", err)
+	}
+	if len(results) == 0 {
+		m.logger.Info("database schema is up to date")
+	}
+	return nil
+}
+
+func (m *Migrator) Down(ctx context.Context) error {
+	result, err := m.provider.Down(ctx)
+	m.logResults(result)
+	if err != nil {
+		return fmt.Errorf("migrate down: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) Redo(ctx context.Context) error {
+	if err := m.Down(ctx); err != nil {
+		return err
+	}
+	result, err := m.provider.UpByOne(ctx)
+	m.logResults(result)
+	if err != nil {
+		return fmt.Errorf("migrate redo: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate status: %w", err)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) Versions(ctx context.Context) (current, target int64, err error) {
+	return m.provider.GetVersions(ctx)
+}
+
+// CheckVersion reports an error unless the database schema is at the version
+// of the newest embedded migration.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	current, target, err := m.Versions(ctx)
+	if err != nil {
+		return fmt.Errorf("read migration versions: %w", err)
+	}
+	if current != target {
+		return fmt.Errorf("database schema at version %d, expected %d", current, target)
+	}
+	return nil
+}
+
+func (m *Migrator) Run(ctx context.Context, command string, out io.Writer) error {
+	switch command {
+	case CommandUp:
+		return m.Up(ctx)
+	case CommandDown:
+		return m.Down(ctx)
+	case CommandRedo:
+		return m.Redo(ctx)
+	case CommandStatus:
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return WriteStatus(out, statuses)
+	}
+	return fmt.Errorf("unknown migrate command %q: expected up, down, status or redo", command)
+}
+
+func WriteStatus(out io.Writer, statuses []*goose.MigrationStatus) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APPLIED AT\tMIGRATION")
+	for _, status := range statuses {
+		appliedAt := "Pending"
+		if status.State == goose.StateApplied {
+			appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\n", appliedAt, filepath.Base(status.Source.Path))
+	}
+	return w.Flush()
+}
+
+func (m *Migrator) logResults(results ...*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		if result.Error != nil {
+			m.logger.Error("migration failed",
+				zap.String("migration", filepath.Base(result.Source.Path)),
+				zap.String("direction", result.Direction),
+				zap.Error(result.Error),
+			)
+			continue
+		}
+		m.logger.Info("migration applied",
+			zap.String("migration", filepath.Base(result.Source.Path)),
+			zap.String("direction", result.Direction),
+			zap.Duration("duration", result.Duration),
+		)
+	}
+}
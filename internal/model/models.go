@@ -1,19 +1,223 @@
 package model
 
+import (
+	"cart-api/pkg/money"
+	"time"
+)
+
+const DefaultTaxCategory = "standard"
+
 type CartItem struct {
-	Id      int
-	CartId  int
-	Product string
-	Price   float64
+	Id          int
+	CartId      int
+	SKU         string
+	Product     string
+	Price       money.Money
+	Quantity    int
+	TaxCategory string
+	Dimensions  Dimensions
+}
+
+// Dimensions describe one unit of a product as packed for shipping.
+type Dimensions struct {
+	WeightGrams int
+	LengthMM    int
+	WidthMM     int
+	HeightMM    int
 }
+
+type CartStatus string
+
+const (
+	CartOpen        CartStatus = "open"
+	CartCheckingOut CartStatus = "checking_out"
+	CartOrdered     CartStatus = "ordered"
+	CartCancelled   CartStatus = "cancelled"
+)
+
+var cartTransitions = map[CartStatus][]CartStatus{
+	CartOpen:        {CartCheckingOut},
+	CartCheckingOut: {CartOrdered, CartCancelled},
+}
+
+func (s CartStatus) CanTransitionTo(next CartStatus) bool {
+	for _, allowed := range cartTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type Cart struct {
-	ID    int
-	Items []CartItem
+	ID             int
+	UserID         string
+	SessionID      string
+	Status         CartStatus
+	Version        int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	AbandonedAt    *time.Time
+	ShippingMethod string
+	Currency       string
+	Items          []CartItem
+}
+
+type Product struct {
+	SKU         string
+	Name        string
+	Price       money.Money
+	TaxCategory string
+	Dimensions  Dimensions
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Stock struct {
+	SKU      string
+	OnHand   int
+	Reserved int
+}
+
+func (s Stock) Available() int {
+	return max(s.OnHand-s.Reserved, 0)
+}
+
+type Reservation struct {
+	CartID    int
+	SKU       string
+	Quantity  int
+	ExpiresAt time.Time
+}
+
+type MergeConflict struct {
+	Product  string
+	Quantity int
+	Reason   string
+}
+
+type MergeResult struct {
+	Cart      *Cart
+	Conflicts []MergeConflict
+}
+
+type Order struct {
+	ID        int
+	CartID    int
+	UserID    string
+	SessionID string
+	Items     []CartItem
+	Price     Price
+	CreatedAt time.Time
 }
 
 type Price struct {
 	CartId          int
-	TotalPrice      float64
+	TotalPrice      money.Money
 	DiscountPercent int
-	FinalPrice      float64
+	DiscountAmount  money.Money
+	Discounts       []AppliedDiscount
+	Coupons         []AppliedCoupon
+	FinalPrice      money.Money
+	Shipping        *AppliedShipping
+	Region          string
+	TaxMode         string
+	Subtotal        money.Money
+	Taxes           []TaxLine
+	TaxTotal        money.Money
+	GrandTotal      money.Money
+	Exchange        *AppliedExchangeRate
+}
+
+type ShippingOption struct {
+	Method   string
+	Name     string
+	Price    money.Money
+	Selected bool
+}
+
+// ExchangeRate converts Base into Quote: one unit of Base buys Rate/1e8 units
+// of Quote.
+type ExchangeRate struct {
+	Base      string
+	Quote     string
+	Rate      int64
+	UpdatedAt time.Time
+}
+
+// AppliedExchangeRate records the rate a price was converted with.
+type AppliedExchangeRate struct {
+	From      string
+	To        string
+	Rate      int64
+	UpdatedAt time.Time
+}
+
+type AppliedShipping struct {
+	Method string
+	Amount money.Money
+}
+
+// TaxRate is the rate applied to a tax category in a region. Rate is in
+// millionths, so 8.875% is 88750.
+type TaxRate struct {
+	Region   string
+	Category string
+	Rate     int64
+}
+
+type TaxLine struct {
+	Category string
+	Rate     int64
+	Taxable  money.Money
+	Amount   money.Money
+}
+
+type AppliedDiscount struct {
+	Rule   string
+	Amount money.Money
+}
+
+type DiscountRule struct {
+	ID       int
+	Name     string
+	Kind     string
+	Priority int
+	Params   []byte
+}
+
+// Coupon is a promotion code. Amount and MinTotal are in the coupon's
+// currency and only apply to carts in that currency.
+type Coupon struct {
+	Code       string
+	Percent    int
+	Amount     money.Money
+	MinTotal   money.Money
+	StartsAt   *time.Time
+	ExpiresAt  *time.Time
+	UsageLimit *int
+	UsedCount  int
+	Stackable  bool
+	Active     bool
+}
+
+type AppliedCoupon struct {
+	Code   string
+	Amount money.Money
+}
+
+type IdempotencyRecord struct {
+	Key         string
+	Route       string
+	Owner       string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
 }
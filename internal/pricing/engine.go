@@ -0,0 +1,86 @@
+package pricing
+
+import (
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Strategy string
+
+const (
+	StrategyBest     Strategy = "best"
+	StrategyStack    Strategy = "stack"
+	StrategyPriority Strategy = "priority"
+)
+
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", StrategyBest:
+		return StrategyBest, nil
+	case StrategyStack:
+		return StrategyStack, nil
+	case StrategyPriority:
+		return StrategyPriority, nil
+	}
+	return "", fmt.Errorf("unknown discount strategy %q", s)
+}
+
+type Engine struct {
+	strategy Strategy
+	rounding money.RoundingMode
+}
+
+func NewEngine(strategy Strategy, rounding money.RoundingMode) *Engine {
+	return &Engine{
+		strategy: strategy,
+		rounding: rounding,
+	}
+}
+
+func (e *Engine) Apply(rules []DiscountRule, in Input) []model.AppliedDiscount {
+	ordered := make([]DiscountRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority() < ordered[j].Priority()
+	})
+
+	var applied []model.AppliedDiscount
+	for _, rule := range ordered {
+		amount, ok := rule.Discount(in, e.rounding)
+		if !ok || amount.Amount <= 0 {
+			continue
+		}
+		applied = append(applied, model.AppliedDiscount{Rule: rule.Name(), Amount: amount})
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	switch e.strategy {
+	case StrategyPriority:
+		return applied[:1]
+	case StrategyStack:
+		remaining := in.Subtotal
+		for i := range applied {
+			if applied[i].Amount.Cmp(remaining) > 0 {
+				applied[i].Amount = remaining
+			}
+			remaining = remaining.Sub(applied[i].Amount)
+			if remaining.IsZero() {
+				return applied[:i+1]
+			}
+		}
+		return applied
+	default:
+		best := applied[0]
+		for _, d := range applied[1:] {
+			if d.Amount.Cmp(best.Amount) > 0 {
+				best = d
+			}
+		}
+		return []model.AppliedDiscount{best}
+	}
+}
@@ -0,0 +1,223 @@
+package pricing
+
+import (
+	"cart-api/internal/exchange"
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.DefaultCurrency)
+}
+
+func buildRule(t *testing.T, name, kind string, priority int, params string) DiscountRule {
+	t.Helper()
+	rule, err := Build(model.DiscountRule{Name: name, Kind: kind, Priority: priority, Params: []byte(params)})
+	require.NoError(t, err)
+	return rule
+}
+
+func TestBuiltinRules(t *testing.T) {
+	items := []model.CartItem{
+		{Product: "Shoes", Price: usd("2500.00"), Quantity: 2},
+		{Product: "Socks", Price: usd("10.00"), Quantity: 5},
+	}
+	in := NewInput(items, money.DefaultCurrency)
+	require.Equal(t, "5050.00", in.Subtotal.String())
+	require.Equal(t, 7, in.Units)
+
+	tests := []struct {
+		name     string
+		kind     string
+		params   string
+		expected string
+		applies  bool
+	}{
+		{name: "total threshold met", kind: KindTotalThreshold, params: `{"threshold": 5000, "percent": 10}`, expected: "505.00", applies: true},
+		{name: "total threshold missed", kind: KindTotalThreshold, params: `{"threshold": 6000, "percent": 10}`},
+		{name: "unit threshold met", kind: KindUnitThreshold, params: `{"threshold": 3, "percent": 5}`, expected: "252.50", applies: true},
+		{name: "unit threshold missed", kind: KindUnitThreshold, params: `{"threshold": 7, "percent": 5}`},
+		{name: "product percent", kind: KindProductPercent, params: `{"product": "Socks", "percent": 50}`, expected: "25.00", applies: true},
+		{name: "product percent absent", kind: KindProductPercent, params: `{"product": "Hat", "percent": 50}`},
+		{name: "fixed amount", kind: KindFixedAmount, params: `{"min_total": "100.00", "amount": "15.00"}`, expected: "15.00", applies: true},
+		{name: "fixed amount below minimum", kind: KindFixedAmount, params: `{"min_total": "9000.00", "amount": "15.00"}`},
+		{name: "buy 2 get 1", kind: KindBuyXGetY, params: `{"product": "Socks", "buy": 2, "free": 1}`, expected: "10.00", applies: true},
+		{name: "buy 5 get 1 not enough units", kind: KindBuyXGetY, params: `{"product": "Socks", "buy": 5, "free": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := buildRule(t, tt.name, tt.kind, 1, tt.params)
+			amount, ok := rule.Discount(in, money.RoundHalfUp)
+			assert.Equal(t, tt.applies, ok)
+			if tt.applies {
+				assert.Equal(t, tt.expected, amount.String())
+				assert.Equal(t, money.DefaultCurrency, amount.Currency)
+			}
+		})
+	}
+}
+
+func TestBuildInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		params string
+	}{
+		{name: "unknown kind", kind: "mystery", params: `{}`},
+		{name: "percent out of range", kind: KindTotalThreshold, params: `{"threshold": 10, "percent": 150}`},
+		{name: "missing product", kind: KindProductPercent, params: `{"percent": 10}`},
+		{name: "zero buy", kind: KindBuyXGetY, params: `{"product": "Socks", "buy": 0, "free": 1}`},
+		{name: "malformed json", kind: KindFixedAmount, params: `{"amount": `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(model.DiscountRule{Name: tt.name, Kind: tt.kind, Params: []byte(tt.params)})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEngineStrategies(t *testing.T) {
+	items := []model.CartItem{{Product: "Shoes", Price: usd("1000.00"), Quantity: 6}}
+	in := NewInput(items, money.DefaultCurrency)
+	rules := []DiscountRule{
+		buildRule(t, "units", KindUnitThreshold, 20, `{"threshold": 3, "percent": 5}`),
+		buildRule(t, "total", KindTotalThreshold, 10, `{"threshold": 5000, "percent": 10}`),
+		buildRule(t, "fixed", KindFixedAmount, 5, `{"amount": "50.00"}`),
+	}
+
+	tests := []struct {
+		strategy Strategy
+		expected []model.AppliedDiscount
+	}{
+		{
+			strategy: StrategyBest,
+			expected: []model.AppliedDiscount{{Rule: "total", Amount: usd("600.00")}},
+		},
+		{
+			strategy: StrategyPriority,
+			expected: []model.AppliedDiscount{{Rule: "fixed", Amount: usd("50.00")}},
+		},
+		{
+			strategy: StrategyStack,
+			expected: []model.AppliedDiscount{
+				{Rule: "fixed", Amount: usd("50.00")},
+				{Rule: "total", Amount: usd("600.00")},
+				{Rule: "units", Amount: usd("300.00")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			engine := NewEngine(tt.strategy, money.RoundHalfUp)
+			assert.Equal(t, tt.expected, engine.Apply(rules, in))
+		})
+	}
+}
+
+func TestEngineStackCapsAtSubtotal(t *testing.T) {
+	in := NewInput([]model.CartItem{{Product: "Pen", Price: usd("30.00"), Quantity: 1}}, money.DefaultCurrency)
+	rules := []DiscountRule{
+		buildRule(t, "first", KindFixedAmount, 1, `{"amount": "20.00"}`),
+		buildRule(t, "second", KindFixedAmount, 2, `{"amount": "20.00"}`),
+		buildRule(t, "third", KindFixedAmount, 3, `{"amount": "20.00"}`),
+	}
+
+	applied := NewEngine(StrategyStack, money.RoundHalfUp).Apply(rules, in)
+
+	assert.Equal(t, []model.AppliedDiscount{
+		{Rule: "first", Amount: usd("20.00")},
+		{Rule: "second", Amount: usd("10.00")},
+	}, applied)
+}
+
+func TestEngineNoRulesApply(t *testing.T) {
+	in := NewInput([]model.CartItem{{Product: "Pen", Price: usd("1.00"), Quantity: 1}}, money.DefaultCurrency)
+
+	assert.Nil(t, NewEngine(StrategyBest, money.RoundHalfUp).Apply(DefaultRules(), in))
+}
+
+func TestRulesConvertConfiguredAmounts(t *testing.T) {
+	items := []model.CartItem{{Product: "Camera", Price: money.MustParse("800000", "JPY"), Quantity: 1}}
+	rules := []DiscountRule{
+		DefaultRules()[0],
+		buildRule(t, "fixed", KindFixedAmount, 20, `{"min_total": "100.00", "amount": "20.00"}`),
+	}
+	engine := NewEngine(StrategyStack, money.RoundHalfUp)
+
+	t.Run("Without Rate", func(t *testing.T) {
+		assert.Nil(t, engine.Apply(rules, NewInput(items, "JPY")))
+	})
+
+	t.Run("With Rate", func(t *testing.T) {
+		in := NewInput(items, "JPY")
+		rate := model.ExchangeRate{Base: money.DefaultCurrency, Quote: "JPY", Rate: 15_000_000_000}
+		in.Convert = exchange.NewConverter("JPY", &rate, money.RoundHalfUp)
+
+		assert.Equal(t, []model.AppliedDiscount{
+			{Rule: "total_over_5000", Amount: money.MustParse("80000", "JPY")},
+			{Rule: "fixed", Amount: money.MustParse("3000", "JPY")},
+		}, engine.Apply(rules, in))
+	})
+}
+
+func TestParseStrategy(t *testing.T) {
+	s, err := ParseStrategy("STACK")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyStack, s)
+
+	_, err = ParseStrategy("random")
+	assert.Error(t, err)
+}
+
+func TestTax(t *testing.T) {
+	items := []model.CartItem{
+		{Product: "Shoes", Price: usd("80.00"), Quantity: 1},
+		{Product: "Bread", Price: usd("10.00"), Quantity: 2, TaxCategory: "food"},
+	}
+	in := NewInput(items, money.DefaultCurrency)
+	rates := []model.TaxRate{
+		{Region: "DE", Category: "standard", Rate: 190000},
+		{Region: "DE", Category: "food", Rate: 70000},
+	}
+	engine := NewEngine(StrategyBest, money.RoundHalfUp)
+
+	t.Run("Exclusive After Discount", func(t *testing.T) {
+		lines := engine.Tax(in, usd("10.00"), rates, TaxExclusive)
+
+		require.Len(t, lines, 2)
+		assert.Equal(t, "food", lines[0].Category)
+		assert.Equal(t, "18.00", lines[0].Taxable.String())
+		assert.Equal(t, "1.26", lines[0].Amount.String())
+		assert.Equal(t, "standard", lines[1].Category)
+		assert.Equal(t, "72.00", lines[1].Taxable.String())
+		assert.Equal(t, "13.68", lines[1].Amount.String())
+	})
+
+	t.Run("Inclusive", func(t *testing.T) {
+		lines := engine.Tax(in, usd("0.00"), rates, TaxInclusive)
+
+		require.Len(t, lines, 2)
+		assert.Equal(t, "1.31", lines[0].Amount.String())
+		assert.Equal(t, "12.77", lines[1].Amount.String())
+	})
+
+	t.Run("Untaxed Category", func(t *testing.T) {
+		lines := engine.Tax(in, usd("0.00"), rates[:1], TaxExclusive)
+
+		require.Len(t, lines, 1)
+		assert.Equal(t, "standard", lines[0].Category)
+	})
+}
+
+func TestFormatRate(t *testing.T) {
+	assert.Equal(t, "8.875", FormatRate(88750))
+	assert.Equal(t, "19", FormatRate(190000))
+}
@@ -0,0 +1,208 @@
+package pricing
+
+import (
+	"cart-api/internal/exchange"
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	KindTotalThreshold = "total_threshold"
+	KindUnitThreshold  = "unit_threshold"
+	KindProductPercent = "product_percent"
+	KindFixedAmount    = "fixed_amount"
+	KindBuyXGetY       = "buy_x_get_y"
+)
+
+// Input is what discount rules look at. Convert turns the amounts of rule
+// params, which are in money.DefaultCurrency, into the currency of Subtotal;
+// rules whose amounts it cannot convert do not apply.
+type Input struct {
+	Items    []model.CartItem
+	Subtotal money.Money
+	Units    int
+	Convert  exchange.Converter
+}
+
+// NewInput returns the input for items priced in currency. Its Convert only
+// accepts amounts already in currency.
+func NewInput(items []model.CartItem, currency string) Input {
+	in := Input{
+		Items:    items,
+		Subtotal: money.New(0, currency),
+		Convert:  exchange.NewConverter(currency, nil, money.RoundHalfUp),
+	}
+	for _, item := range items {
+		in.Units += item.Quantity
+		in.Subtotal = in.Subtotal.Add(item.Price.Mul(int64(item.Quantity)))
+	}
+	return in
+}
+
+type DiscountRule interface {
+	Name() string
+	Priority() int
+	Discount(in Input, mode money.RoundingMode) (money.Money, bool)
+}
+
+type ruleInfo struct {
+	name     string
+	priority int
+}
+
+func (r ruleInfo) Name() string  { return r.name }
+func (r ruleInfo) Priority() int { return r.priority }
+
+type TotalThreshold struct {
+	ruleInfo
+	Threshold money.Money `json:"threshold"`
+	Percent   int64       `json:"percent"`
+}
+
+func (r *TotalThreshold) Discount(in Input, mode money.RoundingMode) (money.Money, bool) {
+	threshold, ok := in.Convert(r.Threshold)
+	if !ok || in.Subtotal.Amount <= threshold.Amount {
+		return money.Money{}, false
+	}
+	return in.Subtotal.Percent(r.Percent, mode), true
+}
+
+type UnitThreshold struct {
+	ruleInfo
+	Threshold int   `json:"threshold"`
+	Percent   int64 `json:"percent"`
+}
+
+func (r *UnitThreshold) Discount(in Input, mode money.RoundingMode) (money.Money, bool) {
+	if in.Units <= r.Threshold {
+		return money.Money{}, false
+	}
+	return in.Subtotal.Percent(r.Percent, mode), true
+}
+
+type ProductPercent struct {
+	ruleInfo
+	Product string `json:"product"`
+	Percent int64  `json:"percent"`
+}
+
+func (r *ProductPercent) Discount(in Input, mode money.RoundingMode) (money.Money, bool) {
+	lineTotal := money.New(0, in.Subtotal.Currency)
+	found := false
+	for _, item := range in.Items {
+		if item.Product == r.Product {
+			lineTotal = lineTotal.Add(item.Price.Mul(int64(item.Quantity)))
+			found = true
+		}
+	}
+	if !found {
+		return money.Money{}, false
+	}
+	return lineTotal.Percent(r.Percent, mode), true
+}
+
+type FixedAmount struct {
+	ruleInfo
+	MinTotal money.Money `json:"min_total"`
+	Amount   money.Money `json:"amount"`
+}
+
+func (r *FixedAmount) Discount(in Input, _ money.RoundingMode) (money.Money, bool) {
+	minTotal, ok := in.Convert(r.MinTotal)
+	if !ok || in.Subtotal.Amount < minTotal.Amount || in.Subtotal.IsZero() {
+		return money.Money{}, false
+	}
+	amount, ok := in.Convert(r.Amount)
+	if !ok {
+		return money.Money{}, false
+	}
+	return money.New(min(amount.Amount, in.Subtotal.Amount), in.Subtotal.Currency), true
+}
+
+type BuyXGetY struct {
+	ruleInfo
+	Product string `json:"product"`
+	Buy     int    `json:"buy"`
+	Free    int    `json:"free"`
+}
+
+func (r *BuyXGetY) Discount(in Input, _ money.RoundingMode) (money.Money, bool) {
+	discount := money.New(0, in.Subtotal.Currency)
+	for _, item := range in.Items {
+		if item.Product != r.Product {
+			continue
+		}
+		freeUnits := item.Quantity / (r.Buy + r.Free) * r.Free
+		discount = discount.Add(item.Price.Mul(int64(freeUnits)))
+	}
+	if discount.IsZero() {
+		return money.Money{}, false
+	}
+	return discount, true
+}
+
+func Build(def model.DiscountRule) (DiscountRule, error) {
+	info := ruleInfo{name: def.Name, priority: def.Priority}
+	var rule DiscountRule
+	var valid func() bool
+	switch def.Kind {
+	case KindTotalThreshold:
+		r := &TotalThreshold{ruleInfo: info}
+		rule, valid = r, func() bool { return validPercent(r.Percent) && !r.Threshold.IsNegative() }
+	case KindUnitThreshold:
+		r := &UnitThreshold{ruleInfo: info}
+		rule, valid = r, func() bool { return validPercent(r.Percent) && r.Threshold >= 0 }
+	case KindProductPercent:
+		r := &ProductPercent{ruleInfo: info}
+		rule, valid = r, func() bool { return validPercent(r.Percent) && r.Product != "" }
+	case KindFixedAmount:
+		r := &FixedAmount{ruleInfo: info}
+		rule, valid = r, func() bool { return r.Amount.Amount > 0 && !r.MinTotal.IsNegative() }
+	case KindBuyXGetY:
+		r := &BuyXGetY{ruleInfo: info}
+		rule, valid = r, func() bool { return r.Product != "" && r.Buy > 0 && r.Free > 0 }
+	default:
+		return nil, fmt.Errorf("discount rule %q: unknown kind %q", def.Name, def.Kind)
+	}
+	if len(def.Params) > 0 {
+		if err := json.Unmarshal(def.Params, rule); err != nil {
+			return nil, fmt.Errorf("discount rule %q: invalid params: %w", def.Name, err)
+		}
+	}
+	switch r := rule.(type) {
+	case *TotalThreshold:
+		r.Threshold.Currency = money.DefaultCurrency
+	case *FixedAmount:
+		r.MinTotal.Currency = money.DefaultCurrency
+		r.Amount.Currency = money.DefaultCurrency
+	}
+	if !valid() {
+		return nil, fmt.Errorf("discount rule %q: invalid params for kind %q", def.Name, def.Kind)
+	}
+	return rule, nil
+}
+
+func BuildAll(defs []model.DiscountRule) ([]DiscountRule, error) {
+	rules := make([]DiscountRule, 0, len(defs))
+	for _, def := range defs {
+		rule, err := Build(def)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func DefaultRules() []DiscountRule {
+	return []DiscountRule{
+		&TotalThreshold{ruleInfo: ruleInfo{name: "total_over_5000", priority: 10}, Threshold: money.MustParse("5000", money.DefaultCurrency), Percent: 10},
+		&UnitThreshold{ruleInfo: ruleInfo{name: "more_than_3_units", priority: 20}, Threshold: 3, Percent: 5},
+	}
+}
+
+func validPercent(p int64) bool {
+	return p > 0 && p <= 100
+}
@@ -0,0 +1,95 @@
+package pricing
+
+import (
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the denominator of model.TaxRate.Rate.
+const RateScale = 1_000_000
+
+type TaxMode string
+
+const (
+	TaxExclusive TaxMode = "exclusive"
+	TaxInclusive TaxMode = "inclusive"
+)
+
+func ParseTaxMode(s string) (TaxMode, error) {
+	switch TaxMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", TaxExclusive:
+		return TaxExclusive, nil
+	case TaxInclusive:
+		return TaxInclusive, nil
+	}
+	return "", fmt.Errorf("unknown tax mode %q", s)
+}
+
+// FormatRate renders a rate in millionths as a percentage, e.g. 88750 as "8.875".
+func FormatRate(rate int64) string {
+	return strconv.FormatFloat(float64(rate)/(RateScale/100), 'f', -1, 64)
+}
+
+// Tax computes one tax line per taxed category. The cart-level discount is
+// spread over the categories in proportion to their share of the subtotal, so
+// tax is charged on what the customer actually pays. In inclusive mode the
+// tax is the part of the price that is tax rather than an amount on top of it.
+func (e *Engine) Tax(in Input, discount money.Money, rates []model.TaxRate, mode TaxMode) []model.TaxLine {
+	if in.Subtotal.Amount <= 0 {
+		return nil
+	}
+	subtotals := make(map[string]money.Money)
+	for _, item := range in.Items {
+		category := item.TaxCategory
+		if category == "" {
+			category = model.DefaultTaxCategory
+		}
+		line := item.Price.Mul(int64(item.Quantity))
+		if current, ok := subtotals[category]; ok {
+			line = current.Add(line)
+		}
+		subtotals[category] = line
+	}
+	categories := make([]string, 0, len(subtotals))
+	for category := range subtotals {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	byCategory := make(map[string]int64, len(rates))
+	for _, rate := range rates {
+		byCategory[rate.Category] = rate.Rate
+	}
+
+	var lines []model.TaxLine
+	remaining := discount
+	for i, category := range categories {
+		share := remaining
+		if i < len(categories)-1 {
+			share = discount.Ratio(subtotals[category].Amount, in.Subtotal.Amount, e.rounding)
+		}
+		remaining = remaining.Sub(share)
+		rate := byCategory[category]
+		if rate <= 0 {
+			continue
+		}
+		taxable := subtotals[category].Sub(share)
+		var amount money.Money
+		if mode == TaxInclusive {
+			amount = taxable.Ratio(rate, RateScale+rate, e.rounding)
+		} else {
+			amount = taxable.Ratio(rate, RateScale, e.rounding)
+		}
+		lines = append(lines, model.TaxLine{
+			Category: category,
+			Rate:     rate,
+			Taxable:  taxable,
+			Amount:   amount,
+		})
+	}
+	return lines
+}
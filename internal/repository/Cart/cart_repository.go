@@ -3,42 +3,150 @@ package Cart
 import (
 	"cart-api/internal/model"
 	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"time"
 )
 
+const itemColumns = "id, cart_id, sku, product, price, currency, quantity, tax_category, weight_grams, length_mm, width_mm, height_mm"
+
+const cartColumns = "id, user_id, session_id, status, version, created_at, updated_at, abandoned_at, shipping_method, currency"
+
+// touchCart prefixes item mutations so the owning cart ($1) gets a new version
+// and counts as active again.
+const touchCart = "WITH touched AS (UPDATE carts SET version = version + 1, updated_at = now(), abandoned_at = NULL WHERE id = $1) "
+
+// touchCartCurrency is touchCart for item inserts: a cart without a currency
+// takes the currency ($5) of its first item.
+const touchCartCurrency = "WITH touched AS (UPDATE carts SET version = version + 1, updated_at = now(), abandoned_at = NULL, currency = COALESCE(currency, $5) WHERE id = $1) "
+
 type CartRepo struct {
-	DB *sqlx.DB
+	DB       *sqlx.DB
+	observer QueryObserver
+}
+
+// QueryObserver is told how long each repository method took.
+type QueryObserver interface {
+	ObserveQuery(method string, d time.Duration)
+}
+
+type Option func(*CartRepo)
+
+func WithQueryObserver(o QueryObserver) Option {
+	return func(r *CartRepo) {
+		r.observer = o
+	}
+}
+
+func New(db *sqlx.DB, opts ...Option) *CartRepo {
+	r := &CartRepo{DB: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *CartRepo) observe(method string, start time.Time) {
+	if r.observer != nil {
+		r.observer.ObserveQuery(method, time.Since(start))
+	}
+}
+
+func (r *CartRepo) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	return postgres.WithTx(ctx, r.DB, fn)
+}
+
+func (r *CartRepo) conn(ctx context.Context) postgres.TracedConn {
+	return postgres.Traced(postgres.Conn(ctx, r.DB))
 }
 
-func New(db *sqlx.DB) *CartRepo {
-	return &CartRepo{db}
+func (r *CartRepo) LockCart(ctx context.Context, cartID int) (*model.Cart, error) {
+	defer r.observe("LockCart", time.Now())
+	return r.findCart(ctx, "SELECT "+cartColumns+" FROM carts WHERE id = $1 FOR NO KEY UPDATE", cartID)
 }
 
-func (r *CartRepo) CreateCart(ctx context.Context) (*model.Cart, error) {
+func (r *CartRepo) findCart(ctx context.Context, query string, cartID int) (*model.Cart, error) {
 	var cartDb dao.CartDb
-	err := r.DB.QueryRowxContext(ctx, "INSERT INTO carts DEFAULT VALUES RETURNING id").Scan(&cartDb.ID)
+	err := r.conn(ctx).QueryRowxContext(ctx, query, cartID).StructScan(&cartDb)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("findCart: query cart error: %w", err)
+	}
+	return cartDb.ToDomain(), nil
+}
+
+func (r *CartRepo) CreateCart(ctx context.Context, cart model.Cart) (*model.Cart, error) {
+	defer r.observe("CreateCart", time.Now())
+	cartDb := dao.NewCartDb(cart)
+	err := r.conn(ctx).QueryRowxContext(ctx, "INSERT INTO carts (user_id, session_id) VALUES ($1, $2) RETURNING "+cartColumns,
+		cartDb.UserID, cartDb.SessionID,
+	).StructScan(&cartDb)
 	if err != nil {
 		return nil, fmt.Errorf("error inserting carts: %w", err)
 	}
 	return cartDb.ToDomain(), nil
 }
 
-func (r *CartRepo) CreateItem(ctx context.Context, item model.CartItem) (int, error) {
+func (r *CartRepo) CreateItem(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	defer r.observe("CreateItem", time.Now())
+	itemDb := dao.NewCartItemDb(item)
+	err := r.conn(ctx).QueryRowxContext(ctx, touchCartCurrency+`INSERT INTO cart_item (cart_id, sku, product, price, currency, quantity, tax_category, weight_grams, length_mm, width_mm, height_mm)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (cart_id, product) DO UPDATE SET quantity = cart_item.quantity + EXCLUDED.quantity, updated_at = now()
+		RETURNING `+itemColumns,
+		itemDb.CartID, itemDb.SKU, itemDb.Product, itemDb.Price, itemDb.Currency, itemDb.Quantity, itemDb.TaxCategory,
+		itemDb.WeightGrams, itemDb.LengthMM, itemDb.WidthMM, itemDb.HeightMM,
+	).StructScan(&itemDb)
+	if err != nil {
+		return nil, fmt.Errorf("CreateItem: upsert item error: %w", err)
+	}
+	created := itemDb.ToDomain()
+	return &created, nil
+}
+
+func (r *CartRepo) UpdateItemQuantity(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	defer r.observe("UpdateItemQuantity", time.Now())
+	itemDb := dao.NewCartItemDb(item)
+	err := r.conn(ctx).QueryRowxContext(ctx, touchCart+`UPDATE cart_item SET quantity = $2, updated_at = now() WHERE id = $3 AND cart_id = $1
+		RETURNING `+itemColumns,
+		itemDb.CartID, itemDb.Quantity, itemDb.ID,
+	).StructScan(&itemDb)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("UpdateItemQuantity: update item error: %w", err)
+	}
+	updated := itemDb.ToDomain()
+	return &updated, nil
+}
+
+func (r *CartRepo) ReplaceItem(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	defer r.observe("ReplaceItem", time.Now())
 	itemDb := dao.NewCartItemDb(item)
-	var newID int
-	err := r.DB.QueryRowxContext(ctx, "INSERT INTO cart_item (cart_id, product, price) VALUES ($1, $2, $3) RETURNING id", itemDb.CartID, itemDb.Product, itemDb.Price).Scan(&newID)
+	err := r.conn(ctx).QueryRowxContext(ctx, touchCart+`UPDATE cart_item SET price = $2, currency = $3, quantity = $4, updated_at = now() WHERE id = $5 AND cart_id = $1
+		RETURNING `+itemColumns,
+		itemDb.CartID, itemDb.Price, itemDb.Currency, itemDb.Quantity, itemDb.ID,
+	).StructScan(&itemDb)
 	if err != nil {
-		return 0, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ReplaceItem: update item error: %w", err)
 	}
-	return newID, nil
+	replaced := itemDb.ToDomain()
+	return &replaced, nil
 }
 
 func (r *CartRepo) DeleteItem(ctx context.Context, item model.CartItem) error {
-	res, err := r.DB.ExecContext(ctx, "DELETE FROM cart_item WHERE id = $1 AND cart_id = $2", item.Id, item.CartId)
+	defer r.observe("DeleteItem", time.Now())
+	res, err := r.conn(ctx).ExecContext(ctx, touchCart+"DELETE FROM cart_item WHERE cart_id = $1 AND id = $2", item.CartId, item.Id)
 	if err != nil {
 		return fmt.Errorf("could not delete item: %w", err)
 	}
@@ -49,9 +157,62 @@ func (r *CartRepo) DeleteItem(ctx context.Context, item model.CartItem) error {
 	return nil
 }
 
+func (r *CartRepo) UpdateCartStatus(ctx context.Context, id int, from, to model.CartStatus) error {
+	defer r.observe("UpdateCartStatus", time.Now())
+	res, err := r.conn(ctx).ExecContext(ctx, "UPDATE carts SET status = $1, version = version + 1, updated_at = now() WHERE id = $2 AND status = $3", to, id, from)
+	if err != nil {
+		return fmt.Errorf("could not update cart status: %w", err)
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *CartRepo) SetShippingMethod(ctx context.Context, id int, method string) error {
+	defer r.observe("SetShippingMethod", time.Now())
+	res, err := r.conn(ctx).ExecContext(ctx, "UPDATE carts SET shipping_method = NULLIF($1, ''), version = version + 1, updated_at = now() WHERE id = $2", method, id)
+	if err != nil {
+		return fmt.Errorf("could not update shipping method: %w", err)
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return &ErrCartNotFound{id}
+	}
+	return nil
+}
+
+// ClaimCart makes the user or session the owner of a cart that has none and
+// reports whether it did; a cart claimed in the meantime is left alone.
+func (r *CartRepo) ClaimCart(ctx context.Context, id int, userID, sessionID string) (bool, error) {
+	defer r.observe("ClaimCart", time.Now())
+	res, err := r.conn(ctx).ExecContext(ctx, `UPDATE carts SET user_id = NULLIF($1, ''), session_id = NULLIF($2, ''), version = version + 1, updated_at = now()
+		WHERE id = $3 AND user_id IS NULL AND session_id IS NULL`, userID, sessionID, id)
+	if err != nil {
+		return false, fmt.Errorf("could not claim cart: %w", err)
+	}
+	count, _ := res.RowsAffected()
+	return count > 0, nil
+}
+
+func (r *CartRepo) DeleteCart(ctx context.Context, id int) error {
+	defer r.observe("DeleteCart", time.Now())
+	res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM carts WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("could not delete cart: %w", err)
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (r *CartRepo) GetCart(ctx context.Context, id int) (*model.Cart, error) {
+	defer r.observe("GetCart", time.Now())
 	var cartDb dao.CartDb
-	err := r.DB.QueryRowxContext(ctx, "SELECT id FROM carts WHERE id = $1", id).Scan(&cartDb.ID)
+	err := r.conn(ctx).QueryRowxContext(ctx, "SELECT "+cartColumns+" FROM carts WHERE id = $1", id).StructScan(&cartDb)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, &ErrCartNotFound{id}
@@ -59,7 +220,7 @@ func (r *CartRepo) GetCart(ctx context.Context, id int) (*model.Cart, error) {
 		return nil, fmt.Errorf("GetCart: query cart error: %w", err)
 	}
 	cart := cartDb.ToDomain()
-	rows, err := r.DB.QueryxContext(ctx, "SELECT id, cart_id, product, price FROM cart_item WHERE cart_id = $1", cart.ID)
+	rows, err := r.conn(ctx).QueryxContext(ctx, "SELECT "+itemColumns+" FROM cart_item WHERE cart_id = $1 ORDER BY id", cart.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, &ErrCartItemNotFound{id, cart.ID}
@@ -70,7 +231,7 @@ func (r *CartRepo) GetCart(ctx context.Context, id int) (*model.Cart, error) {
 
 	for rows.Next() {
 		var itemDb dao.CartItemDb
-		if err = rows.Scan(&itemDb.ID, &itemDb.CartID, &itemDb.Product, &itemDb.Price); err != nil {
+		if err = rows.StructScan(&itemDb); err != nil {
 			return nil, fmt.Errorf("GetCart: scan item error: %w", err)
 		}
 		cart.Items = append(cart.Items, itemDb.ToDomain())
@@ -78,18 +239,34 @@ func (r *CartRepo) GetCart(ctx context.Context, id int) (*model.Cart, error) {
 	return cart, nil
 }
 
-func (r *CartRepo) CartExists(ctx context.Context, cartID int) (bool, error) {
-	var exists bool
-	err := r.DB.QueryRowxContext(ctx, "SELECT EXISTS(SELECT 1 FROM carts WHERE id = $1)", cartID).Scan(&exists)
+func (r *CartRepo) MarkAbandoned(ctx context.Context, idleSince time.Time, limit int) (int64, error) {
+	defer r.observe("MarkAbandoned", time.Now())
+	res, err := r.conn(ctx).ExecContext(ctx, `UPDATE carts SET abandoned_at = now() WHERE id IN (
+		SELECT id FROM carts WHERE status = 'open' AND abandoned_at IS NULL AND updated_at < $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED)`,
+		idleSince, limit,
+	)
 	if err != nil {
-		return false, fmt.Errorf("CartExists: cart isn't found: %w", err)
+		return 0, fmt.Errorf("MarkAbandoned: update carts error: %w", err)
 	}
-	return exists, nil
+	return res.RowsAffected()
+}
+
+func (r *CartRepo) DeleteExpired(ctx context.Context, idleSince time.Time, limit int) (int64, error) {
+	defer r.observe("DeleteExpired", time.Now())
+	res, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM carts WHERE id IN (
+		SELECT id FROM carts WHERE status IN ('open', 'checking_out', 'cancelled') AND updated_at < $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED)`,
+		idleSince, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("DeleteExpired: delete carts error: %w", err)
+	}
+	return res.RowsAffected()
 }
 
 func (r *CartRepo) ItemExists(ctx context.Context, itemID int) (bool, error) {
+	defer r.observe("ItemExists", time.Now())
 	var exists bool
-	err := r.DB.QueryRowxContext(ctx, "SELECT EXISTS(SELECT 1 FROM cart_item WHERE id = $1)", itemID).Scan(&exists)
+	err := r.conn(ctx).QueryRowxContext(ctx, "SELECT EXISTS(SELECT 1 FROM cart_item WHERE id = $1)", itemID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("ItemExists: item isn't exist	: %w", err)
 	}
@@ -0,0 +1,100 @@
+package Coupon
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+const couponColumns = "c.code, c.percent, c.amount, c.min_total, c.starts_at, c.expires_at, c.usage_limit, c.used_count, c.stackable, c.active, c.currency"
+
+type CouponRepo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *CouponRepo {
+	return &CouponRepo{db}
+}
+
+func (r *CouponRepo) GetCoupon(ctx context.Context, code string) (*model.Coupon, error) {
+	var couponDb dao.CouponDb
+	err := r.conn(ctx).QueryRowxContext(ctx, "SELECT "+couponColumns+" FROM coupons c WHERE c.code = $1", code).StructScan(&couponDb)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("GetCoupon: query coupon error: %w", err)
+	}
+	coupon := couponDb.ToDomain()
+	return &coupon, nil
+}
+
+func (r *CouponRepo) ListCartCoupons(ctx context.Context, cartID int) ([]model.Coupon, error) {
+	rows, err := r.conn(ctx).QueryxContext(ctx, "SELECT "+couponColumns+` FROM coupons c
+		JOIN cart_coupons cc ON cc.code = c.code
+		WHERE cc.cart_id = $1
+		ORDER BY cc.applied_at, c.code`, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("ListCartCoupons: query coupons error: %w", err)
+	}
+	defer rows.Close()
+
+	var coupons []model.Coupon
+	for rows.Next() {
+		var couponDb dao.CouponDb
+		if err = rows.StructScan(&couponDb); err != nil {
+			return nil, fmt.Errorf("ListCartCoupons: scan coupon error: %w", err)
+		}
+		coupons = append(coupons, couponDb.ToDomain())
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListCartCoupons: iterate coupons error: %w", err)
+	}
+	return coupons, nil
+}
+
+func (r *CouponRepo) AttachCoupon(ctx context.Context, cartID int, code string) error {
+	res, err := r.conn(ctx).ExecContext(ctx, "INSERT INTO cart_coupons (cart_id, code) VALUES ($1, $2) ON CONFLICT DO NOTHING", cartID, code)
+	if err != nil {
+		return fmt.Errorf("AttachCoupon: insert cart coupon error: %w", err)
+	}
+	if count, _ := res.RowsAffected(); count == 0 {
+		return ErrAlreadyApplied
+	}
+	return nil
+}
+
+func (r *CouponRepo) DetachCoupon(ctx context.Context, cartID int, code string) error {
+	res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM cart_coupons WHERE cart_id = $1 AND code = $2", cartID, code)
+	if err != nil {
+		return fmt.Errorf("DetachCoupon: delete cart coupon error: %w", err)
+	}
+	if count, _ := res.RowsAffected(); count == 0 {
+		return ErrNotApplied
+	}
+	return nil
+}
+
+// RedeemCoupon counts one use of code. Uses are counted when an order is
+// placed, not when a coupon is attached, so carts that are abandoned,
+// merged or deleted never consume the usage limit.
+func (r *CouponRepo) RedeemCoupon(ctx context.Context, code string) error {
+	res, err := r.conn(ctx).ExecContext(ctx, `UPDATE coupons SET used_count = used_count + 1
+		WHERE code = $1 AND (usage_limit IS NULL OR used_count < usage_limit)`, code)
+	if err != nil {
+		return fmt.Errorf("RedeemCoupon: update usage error: %w", err)
+	}
+	if count, _ := res.RowsAffected(); count == 0 {
+		return ErrUsageLimitReached
+	}
+	return nil
+}
+
+func (r *CouponRepo) conn(ctx context.Context) sqlx.ExtContext {
+	return postgres.Conn(ctx, r.DB)
+}
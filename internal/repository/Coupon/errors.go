@@ -0,0 +1,12 @@
+package Coupon
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotFound          = errors.New("coupon not found")
+	ErrNotApplied        = errors.New("coupon is not applied to the cart")
+	ErrAlreadyApplied    = errors.New("coupon is already applied to the cart")
+	ErrUsageLimitReached = errors.New("coupon usage limit reached")
+)
@@ -0,0 +1,39 @@
+package Discount
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+type DiscountRepo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *DiscountRepo {
+	return &DiscountRepo{db}
+}
+
+func (r *DiscountRepo) ListActiveRules(ctx context.Context) ([]model.DiscountRule, error) {
+	rows, err := postgres.Conn(ctx, r.DB).QueryxContext(ctx, "SELECT id, name, kind, priority, params FROM discount_rules WHERE active ORDER BY priority, id")
+	if err != nil {
+		return nil, fmt.Errorf("ListActiveRules: query rules error: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []model.DiscountRule
+	for rows.Next() {
+		var ruleDb dao.DiscountRuleDb
+		if err = rows.Scan(&ruleDb.ID, &ruleDb.Name, &ruleDb.Kind, &ruleDb.Priority, &ruleDb.Params); err != nil {
+			return nil, fmt.Errorf("ListActiveRules: scan rule error: %w", err)
+		}
+		rules = append(rules, ruleDb.ToDomain())
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListActiveRules: iterate rules error: %w", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,56 @@
+package Exchange
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+const rateColumns = "base, quote, (rate * 100000000)::BIGINT AS rate, updated_at"
+
+type ExchangeRepo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *ExchangeRepo {
+	return &ExchangeRepo{db}
+}
+
+func (r *ExchangeRepo) conn(ctx context.Context) sqlx.ExtContext {
+	return postgres.Conn(ctx, r.DB)
+}
+
+// GetExchangeRate returns the rate from base to quote, or nil when the pair
+// is not loaded.
+func (r *ExchangeRepo) GetExchangeRate(ctx context.Context, base, quote string) (*model.ExchangeRate, error) {
+	var rateDb dao.ExchangeRateDb
+	err := r.conn(ctx).QueryRowxContext(ctx, "SELECT "+rateColumns+" FROM exchange_rates WHERE base = $1 AND quote = $2", base, quote).StructScan(&rateDb)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetExchangeRate: query rate error: %w", err)
+	}
+	return rateDb.ToDomain(), nil
+}
+
+// SaveExchangeRates inserts or replaces rates in a single transaction.
+func (r *ExchangeRepo) SaveExchangeRates(ctx context.Context, rates []model.ExchangeRate) error {
+	return postgres.WithTx(ctx, r.DB, func(ctx context.Context) error {
+		for _, rate := range rates {
+			_, err := r.conn(ctx).ExecContext(ctx, `INSERT INTO exchange_rates (base, quote, rate, updated_at)
+				VALUES ($1, $2, $3::NUMERIC / 100000000, $4)
+				ON CONFLICT (base, quote) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at`,
+				rate.Base, rate.Quote, rate.Rate, rate.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("SaveExchangeRates: upsert %s/%s error: %w", rate.Base, rate.Quote, err)
+			}
+		}
+		return nil
+	})
+}
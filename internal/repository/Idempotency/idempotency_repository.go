@@ -0,0 +1,86 @@
+package Idempotency
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+const recordColumns = "idempotency_key, route, owner, request_hash, status_code, content_type, response_body, expires_at"
+
+type IdempotencyRepo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{db}
+}
+
+func (r *IdempotencyRepo) conn(ctx context.Context) sqlx.ExtContext {
+	return postgres.Conn(ctx, r.DB)
+}
+
+// Begin reserves the key for a new request. When the key is already taken by
+// an unexpired record, that record is returned and started is false.
+func (r *IdempotencyRepo) Begin(ctx context.Context, rec model.IdempotencyRecord) (*model.IdempotencyRecord, bool, error) {
+	var recordDb dao.IdempotencyRecordDb
+	err := r.conn(ctx).QueryRowxContext(ctx, `INSERT INTO idempotency_keys (idempotency_key, route, owner, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (idempotency_key, route, owner) DO UPDATE
+			SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL,
+				response_body = NULL, created_at = now(), expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at <= now()
+		RETURNING `+recordColumns,
+		rec.Key, rec.Route, rec.Owner, rec.RequestHash, rec.ExpiresAt,
+	).StructScan(&recordDb)
+	if err == nil {
+		return recordDb.ToDomain(), true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("Begin: insert idempotency key error: %w", err)
+	}
+	err = r.conn(ctx).QueryRowxContext(ctx, "SELECT "+recordColumns+" FROM idempotency_keys WHERE idempotency_key = $1 AND route = $2 AND owner = $3",
+		rec.Key, rec.Route, rec.Owner,
+	).StructScan(&recordDb)
+	if err != nil {
+		return nil, false, fmt.Errorf("Begin: query idempotency key error: %w", err)
+	}
+	return recordDb.ToDomain(), false, nil
+}
+
+func (r *IdempotencyRepo) Complete(ctx context.Context, rec model.IdempotencyRecord) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `UPDATE idempotency_keys SET status_code = $1, content_type = $2, response_body = $3
+		WHERE idempotency_key = $4 AND route = $5 AND owner = $6`,
+		rec.StatusCode, rec.ContentType, rec.Body, rec.Key, rec.Route, rec.Owner,
+	)
+	if err != nil {
+		return fmt.Errorf("Complete: update idempotency key error: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepo) Release(ctx context.Context, rec model.IdempotencyRecord) error {
+	_, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND route = $2 AND owner = $3 AND status_code IS NULL",
+		rec.Key, rec.Route, rec.Owner,
+	)
+	if err != nil {
+		return fmt.Errorf("Release: delete idempotency key error: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepo) PurgeExpired(ctx context.Context, limit int) (int64, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE (idempotency_key, route, owner) IN (
+		SELECT idempotency_key, route, owner FROM idempotency_keys WHERE expires_at <= now() LIMIT $1 FOR UPDATE SKIP LOCKED)`,
+		limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("PurgeExpired: delete idempotency keys error: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,122 @@
+package Inventory
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+// reservedBy sums the live reservations on a SKU held by carts other than $2.
+const reservedBy = `COALESCE((SELECT SUM(quantity) FROM reservations
+	WHERE sku = $1 AND cart_id <> $2 AND expires_at > now()), 0)`
+
+type InventoryRepo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *InventoryRepo {
+	return &InventoryRepo{db}
+}
+
+func (r *InventoryRepo) conn(ctx context.Context) sqlx.ExtContext {
+	return postgres.Conn(ctx, r.DB)
+}
+
+// LockStock locks the inventory row of sku until the surrounding transaction
+// ends and returns it with the quantity reserved by other carts. It returns
+// nil when the SKU is not stock-tracked.
+func (r *InventoryRepo) LockStock(ctx context.Context, sku string, cartID int) (*model.Stock, error) {
+	var stockDb dao.StockDb
+	err := r.conn(ctx).QueryRowxContext(ctx, "SELECT sku, on_hand FROM inventory WHERE sku = $1 FOR UPDATE", sku).StructScan(&stockDb)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("LockStock: query inventory error: %w", err)
+	}
+	err = r.conn(ctx).QueryRowxContext(ctx, "SELECT "+reservedBy, sku, cartID).Scan(&stockDb.Reserved)
+	if err != nil {
+		return nil, fmt.Errorf("LockStock: query reservations error: %w", err)
+	}
+	return stockDb.ToDomain(), nil
+}
+
+func (r *InventoryRepo) GetStock(ctx context.Context, sku string) (*model.Stock, error) {
+	var stockDb dao.StockDb
+	err := r.conn(ctx).QueryRowxContext(ctx, "SELECT sku, on_hand, "+reservedBy+" AS reserved FROM inventory WHERE sku = $1", sku, 0).StructScan(&stockDb)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetStock: query inventory error: %w", err)
+	}
+	return stockDb.ToDomain(), nil
+}
+
+func (r *InventoryRepo) SetStock(ctx context.Context, sku string, onHand int) (*model.Stock, error) {
+	var stockDb dao.StockDb
+	err := r.conn(ctx).QueryRowxContext(ctx, `INSERT INTO inventory (sku, on_hand) VALUES ($1, $3)
+		ON CONFLICT (sku) DO UPDATE SET on_hand = EXCLUDED.on_hand, updated_at = now()
+		RETURNING sku, on_hand, `+reservedBy+` AS reserved`,
+		sku, 0, onHand,
+	).StructScan(&stockDb)
+	if err != nil {
+		return nil, fmt.Errorf("SetStock: upsert inventory error: %w", err)
+	}
+	return stockDb.ToDomain(), nil
+}
+
+func (r *InventoryRepo) Reserve(ctx context.Context, res model.Reservation) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `INSERT INTO reservations (cart_id, sku, quantity, expires_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cart_id, sku) DO UPDATE SET quantity = EXCLUDED.quantity, expires_at = EXCLUDED.expires_at`,
+		res.CartID, res.SKU, res.Quantity, res.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("Reserve: upsert reservation error: %w", err)
+	}
+	return nil
+}
+
+func (r *InventoryRepo) Release(ctx context.Context, cartID int, sku string) error {
+	_, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM reservations WHERE cart_id = $1 AND sku = $2", cartID, sku)
+	if err != nil {
+		return fmt.Errorf("Release: delete reservation error: %w", err)
+	}
+	return nil
+}
+
+func (r *InventoryRepo) ReleaseCart(ctx context.Context, cartID int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM reservations WHERE cart_id = $1", cartID)
+	if err != nil {
+		return fmt.Errorf("ReleaseCart: delete reservations error: %w", err)
+	}
+	return nil
+}
+
+// CommitCart turns the cart's reservations into stock withdrawals.
+func (r *InventoryRepo) CommitCart(ctx context.Context, cartID int) error {
+	_, err := r.conn(ctx).ExecContext(ctx, `WITH taken AS (DELETE FROM reservations WHERE cart_id = $1 RETURNING sku, quantity)
+		UPDATE inventory i SET on_hand = i.on_hand - t.quantity, updated_at = now() FROM taken t WHERE i.sku = t.sku`,
+		cartID,
+	)
+	if err != nil {
+		return fmt.Errorf("CommitCart: update inventory error: %w", err)
+	}
+	return nil
+}
+
+func (r *InventoryRepo) PurgeExpired(ctx context.Context, limit int) (int64, error) {
+	res, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM reservations WHERE (cart_id, sku) IN (
+		SELECT cart_id, sku FROM reservations WHERE expires_at <= now() LIMIT $1 FOR UPDATE SKIP LOCKED)`,
+		limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("PurgeExpired: delete reservations error: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,44 @@
+package Order
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+const orderColumns = "id, cart_id, user_id, session_id, currency, total_price, discount_amount, final_price, tax_total, grand_total, snapshot, created_at"
+
+type OrderRepo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *OrderRepo {
+	return &OrderRepo{db}
+}
+
+func (r *OrderRepo) conn(ctx context.Context) sqlx.ExtContext {
+	return postgres.Conn(ctx, r.DB)
+}
+
+func (r *OrderRepo) CreateOrder(ctx context.Context, order model.Order) (*model.Order, error) {
+	orderDb, err := dao.NewOrderDb(order)
+	if err != nil {
+		return nil, fmt.Errorf("CreateOrder: encode snapshot error: %w", err)
+	}
+	err = r.conn(ctx).QueryRowxContext(ctx, `INSERT INTO orders (cart_id, user_id, session_id, currency, total_price, discount_amount, final_price, tax_total, grand_total, snapshot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING `+orderColumns,
+		orderDb.CartID, orderDb.UserID, orderDb.SessionID, orderDb.Currency,
+		orderDb.TotalPrice, orderDb.DiscountAmount, orderDb.FinalPrice, orderDb.TaxTotal, orderDb.GrandTotal, orderDb.Snapshot,
+	).StructScan(&orderDb)
+	if err != nil {
+		return nil, fmt.Errorf("CreateOrder: insert order error: %w", err)
+	}
+	created, err := orderDb.ToDomain()
+	if err != nil {
+		return nil, fmt.Errorf("CreateOrder: decode snapshot error: %w", err)
+	}
+	return created, nil
+}
@@ -0,0 +1,8 @@
+package Product
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("product not found")
+	ErrAlreadyExists = errors.New("product with this SKU or name already exists")
+)
@@ -0,0 +1,107 @@
+package Product
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+const productColumns = "sku, name, price, currency, tax_category, weight_grams, length_mm, width_mm, height_mm, active, created_at, updated_at"
+
+type ProductRepo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *ProductRepo {
+	return &ProductRepo{db}
+}
+
+func (r *ProductRepo) conn(ctx context.Context) sqlx.ExtContext {
+	return postgres.Conn(ctx, r.DB)
+}
+
+func (r *ProductRepo) ListProducts(ctx context.Context, includeInactive bool) ([]model.Product, error) {
+	rows, err := r.conn(ctx).QueryxContext(ctx, "SELECT "+productColumns+" FROM products WHERE active OR $1 ORDER BY sku", includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("ListProducts: query products error: %w", err)
+	}
+	defer rows.Close()
+
+	var products []model.Product
+	for rows.Next() {
+		var productDb dao.ProductDb
+		if err = rows.StructScan(&productDb); err != nil {
+			return nil, fmt.Errorf("ListProducts: scan product error: %w", err)
+		}
+		products = append(products, productDb.ToDomain())
+	}
+	return products, rows.Err()
+}
+
+func (r *ProductRepo) GetProduct(ctx context.Context, sku string) (*model.Product, error) {
+	var productDb dao.ProductDb
+	err := r.conn(ctx).QueryRowxContext(ctx, "SELECT "+productColumns+" FROM products WHERE sku = $1", sku).StructScan(&productDb)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetProduct: query product error: %w", err)
+	}
+	product := productDb.ToDomain()
+	return &product, nil
+}
+
+func (r *ProductRepo) CreateProduct(ctx context.Context, product model.Product) (*model.Product, error) {
+	productDb := dao.NewProductDb(product)
+	err := r.conn(ctx).QueryRowxContext(ctx, `INSERT INTO products (sku, name, price, currency, tax_category, weight_grams, length_mm, width_mm, height_mm, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING `+productColumns,
+		productDb.SKU, productDb.Name, productDb.Price, productDb.Currency, productDb.TaxCategory,
+		productDb.WeightGrams, productDb.LengthMM, productDb.WidthMM, productDb.HeightMM, productDb.Active,
+	).StructScan(&productDb)
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("CreateProduct: insert product error: %w", err)
+	}
+	created := productDb.ToDomain()
+	return &created, nil
+}
+
+func (r *ProductRepo) UpdateProduct(ctx context.Context, product model.Product) (*model.Product, error) {
+	productDb := dao.NewProductDb(product)
+	err := r.conn(ctx).QueryRowxContext(ctx, `UPDATE products SET name = $1, price = $2, currency = $3, tax_category = $4,
+		weight_grams = $5, length_mm = $6, width_mm = $7, height_mm = $8, active = $9, updated_at = now()
+		WHERE sku = $10 RETURNING `+productColumns,
+		productDb.Name, productDb.Price, productDb.Currency, productDb.TaxCategory,
+		productDb.WeightGrams, productDb.LengthMM, productDb.WidthMM, productDb.HeightMM, productDb.Active, productDb.SKU,
+	).StructScan(&productDb)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		if postgres.IsUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("UpdateProduct: update product error: %w", err)
+	}
+	updated := productDb.ToDomain()
+	return &updated, nil
+}
+
+func (r *ProductRepo) DeleteProduct(ctx context.Context, sku string) error {
+	res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM products WHERE sku = $1", sku)
+	if err != nil {
+		return fmt.Errorf("DeleteProduct: delete product error: %w", err)
+	}
+	count, _ := res.RowsAffected()
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
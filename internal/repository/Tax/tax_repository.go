@@ -0,0 +1,40 @@
+package Tax
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/dao"
+	"cart-api/pkg/database/postgres"
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+type TaxRepo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *TaxRepo {
+	return &TaxRepo{db}
+}
+
+func (r *TaxRepo) ListTaxRates(ctx context.Context, region string) ([]model.TaxRate, error) {
+	rows, err := postgres.Conn(ctx, r.DB).QueryxContext(ctx,
+		"SELECT region, category, (rate * 10000)::BIGINT AS rate FROM tax_rates WHERE region = $1 ORDER BY category", region)
+	if err != nil {
+		return nil, fmt.Errorf("ListTaxRates: query rates error: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []model.TaxRate
+	for rows.Next() {
+		var rateDb dao.TaxRateDb
+		if err = rows.StructScan(&rateDb); err != nil {
+			return nil, fmt.Errorf("ListTaxRates: scan rate error: %w", err)
+		}
+		rates = append(rates, rateDb.ToDomain())
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListTaxRates: iterate rates error: %w", err)
+	}
+	return rates, nil
+}
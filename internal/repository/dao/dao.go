@@ -1,39 +1,439 @@
 package dao
 
-import "cart-api/internal/model"
+import (
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
 
 type CartDb struct {
-	ID int `db:"id"`
+	ID             int            `db:"id"`
+	UserID         sql.NullString `db:"user_id"`
+	SessionID      sql.NullString `db:"session_id"`
+	Status         string         `db:"status"`
+	Version        int            `db:"version"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+	AbandonedAt    sql.NullTime   `db:"abandoned_at"`
+	ShippingMethod sql.NullString `db:"shipping_method"`
+	Currency       sql.NullString `db:"currency"`
+}
+
+func NewCartDb(cart model.Cart) CartDb {
+	return CartDb{
+		ID:        cart.ID,
+		UserID:    sql.NullString{String: cart.UserID, Valid: cart.UserID != ""},
+		SessionID: sql.NullString{String: cart.SessionID, Valid: cart.SessionID != ""},
+	}
 }
 
 type CartItemDb struct {
-	ID      int     `db:"id"`
-	CartID  int     `db:"cart_id"`
-	Product string  `db:"product"`
-	Price   float64 `db:"price"`
+	ID          int            `db:"id"`
+	CartID      int            `db:"cart_id"`
+	SKU         sql.NullString `db:"sku"`
+	Product     string         `db:"product"`
+	Price       money.Money    `db:"price"`
+	Currency    string         `db:"currency"`
+	Quantity    int            `db:"quantity"`
+	TaxCategory string         `db:"tax_category"`
+	DimensionsDb
+}
+
+type DimensionsDb struct {
+	WeightGrams int `db:"weight_grams"`
+	LengthMM    int `db:"length_mm"`
+	WidthMM     int `db:"width_mm"`
+	HeightMM    int `db:"height_mm"`
+}
+
+func NewDimensionsDb(d model.Dimensions) DimensionsDb {
+	return DimensionsDb{
+		WeightGrams: d.WeightGrams,
+		LengthMM:    d.LengthMM,
+		WidthMM:     d.WidthMM,
+		HeightMM:    d.HeightMM,
+	}
+}
+
+func (dbDimensions DimensionsDb) ToDomain() model.Dimensions {
+	return model.Dimensions{
+		WeightGrams: dbDimensions.WeightGrams,
+		LengthMM:    dbDimensions.LengthMM,
+		WidthMM:     dbDimensions.WidthMM,
+		HeightMM:    dbDimensions.HeightMM,
+	}
 }
 
 func (dbItem *CartItemDb) ToDomain() model.CartItem {
 	return model.CartItem{
-		Id:      dbItem.ID,
-		CartId:  dbItem.CartID,
-		Product: dbItem.Product,
-		Price:   dbItem.Price,
+		Id:          dbItem.ID,
+		CartId:      dbItem.CartID,
+		SKU:         dbItem.SKU.String,
+		Product:     dbItem.Product,
+		Price:       dbItem.Price.In(dbItem.Currency),
+		Quantity:    dbItem.Quantity,
+		TaxCategory: dbItem.TaxCategory,
+		Dimensions:  dbItem.DimensionsDb.ToDomain(),
 	}
 }
 
 func NewCartItemDb(item model.CartItem) CartItemDb {
+	taxCategory := item.TaxCategory
+	if taxCategory == "" {
+		taxCategory = model.DefaultTaxCategory
+	}
 	return CartItemDb{
-		ID:      item.Id,
-		CartID:  item.CartId,
-		Product: item.Product,
-		Price:   item.Price,
+		ID:           item.Id,
+		CartID:       item.CartId,
+		SKU:          sql.NullString{String: item.SKU, Valid: item.SKU != ""},
+		Product:      item.Product,
+		Price:        item.Price,
+		Currency:     item.Price.Currency,
+		Quantity:     item.Quantity,
+		TaxCategory:  taxCategory,
+		DimensionsDb: NewDimensionsDb(item.Dimensions),
 	}
 }
 
 func (dbCart *CartDb) ToDomain() *model.Cart {
-	return &model.Cart{
-		ID:    dbCart.ID,
-		Items: []model.CartItem{},
+	cart := &model.Cart{
+		ID:             dbCart.ID,
+		UserID:         dbCart.UserID.String,
+		SessionID:      dbCart.SessionID.String,
+		Status:         model.CartStatus(dbCart.Status),
+		Version:        dbCart.Version,
+		CreatedAt:      dbCart.CreatedAt,
+		UpdatedAt:      dbCart.UpdatedAt,
+		Items:          []model.CartItem{},
+		ShippingMethod: dbCart.ShippingMethod.String,
+		Currency:       dbCart.Currency.String,
+	}
+	if dbCart.AbandonedAt.Valid {
+		cart.AbandonedAt = &dbCart.AbandonedAt.Time
+	}
+	return cart
+}
+
+type DiscountRuleDb struct {
+	ID       int    `db:"id"`
+	Name     string `db:"name"`
+	Kind     string `db:"kind"`
+	Priority int    `db:"priority"`
+	Params   []byte `db:"params"`
+}
+
+func (dbRule *DiscountRuleDb) ToDomain() model.DiscountRule {
+	return model.DiscountRule{
+		ID:       dbRule.ID,
+		Name:     dbRule.Name,
+		Kind:     dbRule.Kind,
+		Priority: dbRule.Priority,
+		Params:   dbRule.Params,
+	}
+}
+
+type CouponDb struct {
+	Code       string        `db:"code"`
+	Percent    int           `db:"percent"`
+	Amount     money.Money   `db:"amount"`
+	MinTotal   money.Money   `db:"min_total"`
+	StartsAt   sql.NullTime  `db:"starts_at"`
+	ExpiresAt  sql.NullTime  `db:"expires_at"`
+	UsageLimit sql.NullInt64 `db:"usage_limit"`
+	UsedCount  int           `db:"used_count"`
+	Stackable  bool          `db:"stackable"`
+	Active     bool          `db:"active"`
+	Currency   string        `db:"currency"`
+}
+
+func (dbCoupon *CouponDb) ToDomain() model.Coupon {
+	coupon := model.Coupon{
+		Code:      dbCoupon.Code,
+		Percent:   dbCoupon.Percent,
+		Amount:    dbCoupon.Amount.In(dbCoupon.Currency),
+		MinTotal:  dbCoupon.MinTotal.In(dbCoupon.Currency),
+		UsedCount: dbCoupon.UsedCount,
+		Stackable: dbCoupon.Stackable,
+		Active:    dbCoupon.Active,
+	}
+	if dbCoupon.StartsAt.Valid {
+		coupon.StartsAt = &dbCoupon.StartsAt.Time
+	}
+	if dbCoupon.ExpiresAt.Valid {
+		coupon.ExpiresAt = &dbCoupon.ExpiresAt.Time
+	}
+	if dbCoupon.UsageLimit.Valid {
+		limit := int(dbCoupon.UsageLimit.Int64)
+		coupon.UsageLimit = &limit
+	}
+	return coupon
+}
+
+type OrderDb struct {
+	ID             int            `db:"id"`
+	CartID         sql.NullInt64  `db:"cart_id"`
+	UserID         sql.NullString `db:"user_id"`
+	SessionID      sql.NullString `db:"session_id"`
+	Currency       string         `db:"currency"`
+	TotalPrice     money.Money    `db:"total_price"`
+	DiscountAmount money.Money    `db:"discount_amount"`
+	FinalPrice     money.Money    `db:"final_price"`
+	TaxTotal       money.Money    `db:"tax_total"`
+	GrandTotal     money.Money    `db:"grand_total"`
+	Snapshot       []byte         `db:"snapshot"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+type OrderSnapshot struct {
+	Items           []OrderItemSnapshot     `json:"items"`
+	DiscountPercent int                     `json:"discount_percent"`
+	Discounts       []OrderDiscountSnapshot `json:"discounts"`
+	Coupons         []OrderDiscountSnapshot `json:"coupons"`
+	Shipping        *OrderDiscountSnapshot  `json:"shipping,omitempty"`
+	Region          string                  `json:"region,omitempty"`
+	TaxMode         string                  `json:"tax_mode,omitempty"`
+	Subtotal        money.Money             `json:"subtotal"`
+	Taxes           []OrderTaxSnapshot      `json:"taxes"`
+}
+
+type OrderTaxSnapshot struct {
+	Category string      `json:"category"`
+	Rate     int64       `json:"rate"`
+	Taxable  money.Money `json:"taxable"`
+	Amount   money.Money `json:"amount"`
+}
+
+type OrderItemSnapshot struct {
+	ID          int         `json:"id"`
+	SKU         string      `json:"sku,omitempty"`
+	Product     string      `json:"product"`
+	Price       money.Money `json:"price"`
+	Currency    string      `json:"currency"`
+	Quantity    int         `json:"quantity"`
+	TaxCategory string      `json:"tax_category,omitempty"`
+}
+
+type OrderDiscountSnapshot struct {
+	Name   string      `json:"name"`
+	Amount money.Money `json:"amount"`
+}
+
+func NewOrderDb(order model.Order) (OrderDb, error) {
+	snapshot := OrderSnapshot{
+		Items:           make([]OrderItemSnapshot, 0, len(order.Items)),
+		DiscountPercent: order.Price.DiscountPercent,
+		Discounts:       make([]OrderDiscountSnapshot, 0, len(order.Price.Discounts)),
+		Coupons:         make([]OrderDiscountSnapshot, 0, len(order.Price.Coupons)),
+		Region:          order.Price.Region,
+		TaxMode:         order.Price.TaxMode,
+		Subtotal:        order.Price.Subtotal,
+		Taxes:           make([]OrderTaxSnapshot, 0, len(order.Price.Taxes)),
+	}
+	for _, item := range order.Items {
+		snapshot.Items = append(snapshot.Items, OrderItemSnapshot{
+			ID:          item.Id,
+			SKU:         item.SKU,
+			Product:     item.Product,
+			Price:       item.Price,
+			Currency:    item.Price.Currency,
+			Quantity:    item.Quantity,
+			TaxCategory: item.TaxCategory,
+		})
+	}
+	for _, discount := range order.Price.Discounts {
+		snapshot.Discounts = append(snapshot.Discounts, OrderDiscountSnapshot{Name: discount.Rule, Amount: discount.Amount})
+	}
+	for _, coupon := range order.Price.Coupons {
+		snapshot.Coupons = append(snapshot.Coupons, OrderDiscountSnapshot{Name: coupon.Code, Amount: coupon.Amount})
+	}
+	if order.Price.Shipping != nil {
+		snapshot.Shipping = &OrderDiscountSnapshot{Name: order.Price.Shipping.Method, Amount: order.Price.Shipping.Amount}
+	}
+	for _, tax := range order.Price.Taxes {
+		snapshot.Taxes = append(snapshot.Taxes, OrderTaxSnapshot{Category: tax.Category, Rate: tax.Rate, Taxable: tax.Taxable, Amount: tax.Amount})
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return OrderDb{}, err
+	}
+	return OrderDb{
+		ID:             order.ID,
+		CartID:         sql.NullInt64{Int64: int64(order.CartID), Valid: order.CartID != 0},
+		UserID:         sql.NullString{String: order.UserID, Valid: order.UserID != ""},
+		SessionID:      sql.NullString{String: order.SessionID, Valid: order.SessionID != ""},
+		Currency:       order.Price.TotalPrice.Currency,
+		TotalPrice:     order.Price.TotalPrice,
+		DiscountAmount: order.Price.DiscountAmount,
+		FinalPrice:     order.Price.FinalPrice,
+		TaxTotal:       order.Price.TaxTotal,
+		GrandTotal:     order.Price.GrandTotal,
+		Snapshot:       data,
+		CreatedAt:      order.CreatedAt,
+	}, nil
+}
+
+func (dbOrder *OrderDb) ToDomain() (*model.Order, error) {
+	var snapshot OrderSnapshot
+	if err := json.Unmarshal(dbOrder.Snapshot, &snapshot); err != nil {
+		return nil, err
+	}
+	currency := dbOrder.Currency
+	order := &model.Order{
+		ID:        dbOrder.ID,
+		CartID:    int(dbOrder.CartID.Int64),
+		UserID:    dbOrder.UserID.String,
+		SessionID: dbOrder.SessionID.String,
+		Items:     make([]model.CartItem, 0, len(snapshot.Items)),
+		Price: model.Price{
+			CartId:          int(dbOrder.CartID.Int64),
+			TotalPrice:      dbOrder.TotalPrice.In(currency),
+			DiscountPercent: snapshot.DiscountPercent,
+			DiscountAmount:  dbOrder.DiscountAmount.In(currency),
+			FinalPrice:      dbOrder.FinalPrice.In(currency),
+			Region:          snapshot.Region,
+			TaxMode:         snapshot.TaxMode,
+			Subtotal:        snapshot.Subtotal.In(currency),
+			TaxTotal:        dbOrder.TaxTotal.In(currency),
+			GrandTotal:      dbOrder.GrandTotal.In(currency),
+		},
+		CreatedAt: dbOrder.CreatedAt,
+	}
+	for _, item := range snapshot.Items {
+		order.Items = append(order.Items, model.CartItem{
+			Id:          item.ID,
+			CartId:      order.CartID,
+			SKU:         item.SKU,
+			Product:     item.Product,
+			Price:       item.Price.In(item.Currency),
+			Quantity:    item.Quantity,
+			TaxCategory: item.TaxCategory,
+		})
+	}
+	for _, discount := range snapshot.Discounts {
+		order.Price.Discounts = append(order.Price.Discounts, model.AppliedDiscount{Rule: discount.Name, Amount: discount.Amount.In(currency)})
+	}
+	for _, coupon := range snapshot.Coupons {
+		order.Price.Coupons = append(order.Price.Coupons, model.AppliedCoupon{Code: coupon.Name, Amount: coupon.Amount.In(currency)})
+	}
+	if snapshot.Shipping != nil {
+		order.Price.Shipping = &model.AppliedShipping{Method: snapshot.Shipping.Name, Amount: snapshot.Shipping.Amount.In(currency)}
+	}
+	for _, tax := range snapshot.Taxes {
+		order.Price.Taxes = append(order.Price.Taxes, model.TaxLine{
+			Category: tax.Category,
+			Rate:     tax.Rate,
+			Taxable:  tax.Taxable.In(currency),
+			Amount:   tax.Amount.In(currency),
+		})
+	}
+	return order, nil
+}
+
+type IdempotencyRecordDb struct {
+	Key         string         `db:"idempotency_key"`
+	Route       string         `db:"route"`
+	Owner       string         `db:"owner"`
+	RequestHash string         `db:"request_hash"`
+	StatusCode  sql.NullInt64  `db:"status_code"`
+	ContentType sql.NullString `db:"content_type"`
+	Body        []byte         `db:"response_body"`
+	ExpiresAt   time.Time      `db:"expires_at"`
+}
+
+func (dbRecord *IdempotencyRecordDb) ToDomain() *model.IdempotencyRecord {
+	return &model.IdempotencyRecord{
+		Key:         dbRecord.Key,
+		Route:       dbRecord.Route,
+		Owner:       dbRecord.Owner,
+		RequestHash: dbRecord.RequestHash,
+		StatusCode:  int(dbRecord.StatusCode.Int64),
+		ContentType: dbRecord.ContentType.String,
+		Body:        dbRecord.Body,
+		ExpiresAt:   dbRecord.ExpiresAt,
+	}
+}
+
+type ProductDb struct {
+	SKU         string      `db:"sku"`
+	Name        string      `db:"name"`
+	Price       money.Money `db:"price"`
+	Currency    string      `db:"currency"`
+	TaxCategory string      `db:"tax_category"`
+	Active      bool        `db:"active"`
+	DimensionsDb
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func NewProductDb(product model.Product) ProductDb {
+	return ProductDb{
+		SKU:          product.SKU,
+		Name:         product.Name,
+		Price:        product.Price,
+		Currency:     product.Price.Currency,
+		TaxCategory:  product.TaxCategory,
+		Active:       product.Active,
+		DimensionsDb: NewDimensionsDb(product.Dimensions),
+		CreatedAt:    product.CreatedAt,
+		UpdatedAt:    product.UpdatedAt,
+	}
+}
+
+func (dbProduct *ProductDb) ToDomain() model.Product {
+	return model.Product{
+		SKU:         dbProduct.SKU,
+		Name:        dbProduct.Name,
+		Price:       dbProduct.Price.In(dbProduct.Currency),
+		TaxCategory: dbProduct.TaxCategory,
+		Dimensions:  dbProduct.DimensionsDb.ToDomain(),
+		Active:      dbProduct.Active,
+		CreatedAt:   dbProduct.CreatedAt,
+		UpdatedAt:   dbProduct.UpdatedAt,
+	}
+}
+
+type StockDb struct {
+	SKU      string `db:"sku"`
+	OnHand   int    `db:"on_hand"`
+	Reserved int    `db:"reserved"`
+}
+
+func (dbStock *StockDb) ToDomain() *model.Stock {
+	return &model.Stock{
+		SKU:      dbStock.SKU,
+		OnHand:   dbStock.OnHand,
+		Reserved: dbStock.Reserved,
+	}
+}
+
+type TaxRateDb struct {
+	Region   string `db:"region"`
+	Category string `db:"category"`
+	Rate     int64  `db:"rate"`
+}
+
+func (dbRate *TaxRateDb) ToDomain() model.TaxRate {
+	return model.TaxRate{
+		Region:   dbRate.Region,
+		Category: dbRate.Category,
+		Rate:     dbRate.Rate,
+	}
+}
+
+type ExchangeRateDb struct {
+	Base      string    `db:"base"`
+	Quote     string    `db:"quote"`
+	Rate      int64     `db:"rate"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (dbRate *ExchangeRateDb) ToDomain() *model.ExchangeRate {
+	return &model.ExchangeRate{
+		Base:      dbRate.Base,
+		Quote:     dbRate.Quote,
+		Rate:      dbRate.Rate,
+		UpdatedAt: dbRate.UpdatedAt,
 	}
 }
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"cart-api/internal/auth"
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	skuPattern         = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+	taxCategoryPattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+)
+
+type ProductRepository interface {
+	ListProducts(context.Context, bool) ([]model.Product, error)
+	GetProduct(context.Context, string) (*model.Product, error)
+	CreateProduct(context.Context, model.Product) (*model.Product, error)
+	UpdateProduct(context.Context, model.Product) (*model.Product, error)
+	DeleteProduct(context.Context, string) error
+}
+
+type StockRepository interface {
+	GetStock(context.Context, string) (*model.Stock, error)
+	SetStock(context.Context, string, int) (*model.Stock, error)
+}
+
+type CatalogService struct {
+	ProductRepo ProductRepository
+	StockRepo   StockRepository
+}
+
+func NewCatalogService(productRepo ProductRepository, stockRepo StockRepository) *CatalogService {
+	return &CatalogService{ProductRepo: productRepo, StockRepo: stockRepo}
+}
+
+// ListProducts returns active products; administrators also see inactive ones.
+func (s *CatalogService) ListProducts(ctx context.Context) ([]model.Product, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return s.ProductRepo.ListProducts(ctx, principal.Admin)
+}
+
+func (s *CatalogService) GetProduct(ctx context.Context, sku string) (*model.Product, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	product, err := s.ProductRepo.GetProduct(ctx, sku)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil || (!product.Active && !principal.Admin) {
+		return nil, ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (s *CatalogService) CreateProduct(ctx context.Context, product model.Product) (*model.Product, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	product, err := validateProduct(product)
+	if err != nil {
+		return nil, err
+	}
+	return s.ProductRepo.CreateProduct(ctx, product)
+}
+
+func (s *CatalogService) UpdateProduct(ctx context.Context, product model.Product) (*model.Product, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	product, err := validateProduct(product)
+	if err != nil {
+		return nil, err
+	}
+	return s.ProductRepo.UpdateProduct(ctx, product)
+}
+
+func (s *CatalogService) DeleteProduct(ctx context.Context, sku string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	return s.ProductRepo.DeleteProduct(ctx, sku)
+}
+
+func (s *CatalogService) GetStock(ctx context.Context, sku string) (*model.Stock, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	stock, err := s.StockRepo.GetStock(ctx, sku)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock: %w", err)
+	}
+	if stock == nil {
+		return nil, ErrProductNotFound
+	}
+	return stock, nil
+}
+
+func (s *CatalogService) SetStock(ctx context.Context, sku string, onHand int) (*model.Stock, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if onHand < 0 {
+		return nil, ErrInvalidQuantity
+	}
+	product, err := s.ProductRepo.GetProduct(ctx, sku)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+	return s.StockRepo.SetStock(ctx, sku, onHand)
+}
+
+func validateProduct(product model.Product) (model.Product, error) {
+	if !skuPattern.MatchString(product.SKU) {
+		return product, ErrInvalidSKU
+	}
+	product.Name = strings.TrimSpace(product.Name)
+	if product.Name == "" {
+		return product, ErrInvalidProduct
+	}
+	if product.Price.IsNegative() {
+		return product, ErrInvalidPrice
+	}
+	if product.Price.Currency == "" {
+		product.Price.Currency = money.DefaultCurrency
+	}
+	if !money.ValidCurrency(product.Price.Currency) {
+		return product, ErrInvalidCurrency
+	}
+	if product.Price.Cmp(money.MaxAmount(product.Price.Currency)) > 0 {
+		return product, ErrInvalidPrice
+	}
+	if product.TaxCategory == "" {
+		product.TaxCategory = model.DefaultTaxCategory
+	}
+	if !taxCategoryPattern.MatchString(product.TaxCategory) {
+		return product, ErrInvalidTaxCategory
+	}
+	d := product.Dimensions
+	if d.WeightGrams < 0 || d.LengthMM < 0 || d.WidthMM < 0 || d.HeightMM < 0 {
+		return product, ErrInvalidDimensions
+	}
+	return product, nil
+}
+
+func requireAdmin(ctx context.Context) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if !principal.Admin {
+		return ErrAdminRequired
+	}
+	return nil
+}
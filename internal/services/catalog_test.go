@@ -0,0 +1,170 @@
+package services
+
+import (
+	"cart-api/internal/auth"
+	"cart-api/internal/model"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProductRepo struct {
+	mock.Mock
+}
+
+func (m *MockProductRepo) ListProducts(ctx context.Context, includeInactive bool) ([]model.Product, error) {
+	args := m.Called(ctx, includeInactive)
+	return args.Get(0).([]model.Product), args.Error(1)
+}
+
+func (m *MockProductRepo) GetProduct(ctx context.Context, sku string) (*model.Product, error) {
+	args := m.Called(ctx, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockProductRepo) CreateProduct(ctx context.Context, product model.Product) (*model.Product, error) {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockProductRepo) UpdateProduct(ctx context.Context, product model.Product) (*model.Product, error) {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockProductRepo) DeleteProduct(ctx context.Context, sku string) error {
+	return m.Called(ctx, sku).Error(0)
+}
+
+func adminCtx() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: "admin-1", Admin: true})
+}
+
+func TestCatalogAdminOnly(t *testing.T) {
+	product := model.Product{SKU: "SHO-1", Name: "Shoes", Price: usd("100.00"), TaxCategory: model.DefaultTaxCategory, Active: true}
+
+	t.Run("Admin", func(t *testing.T) {
+		mockRepo := new(MockProductRepo)
+		mockRepo.On("CreateProduct", mock.Anything, product).Return(&product, nil)
+
+		created, err := NewCatalogService(mockRepo, nil).CreateProduct(adminCtx(), product)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "SHO-1", created.SKU)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Admin", func(t *testing.T) {
+		mockRepo := new(MockProductRepo)
+		service := NewCatalogService(mockRepo, nil)
+
+		_, err := service.CreateProduct(userCtx(), product)
+		assert.ErrorIs(t, err, ErrAdminRequired)
+		_, err = service.UpdateProduct(userCtx(), product)
+		assert.ErrorIs(t, err, ErrAdminRequired)
+		assert.ErrorIs(t, service.DeleteProduct(userCtx(), "SHO-1"), ErrAdminRequired)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid SKU", func(t *testing.T) {
+		invalid := product
+		invalid.SKU = "shoes and socks"
+
+		_, err := NewCatalogService(new(MockProductRepo), nil).CreateProduct(adminCtx(), invalid)
+
+		assert.ErrorIs(t, err, ErrInvalidSKU)
+	})
+
+	t.Run("Price Too Large To Store", func(t *testing.T) {
+		invalid := product
+		invalid.Price = usd("100000000.00")
+
+		_, err := NewCatalogService(new(MockProductRepo), nil).CreateProduct(adminCtx(), invalid)
+
+		assert.ErrorIs(t, err, ErrInvalidPrice)
+	})
+}
+
+func TestCatalogGetProduct(t *testing.T) {
+	inactive := &model.Product{SKU: "OLD-1", Name: "Old", Price: usd("1.00")}
+
+	t.Run("Inactive Hidden From Shoppers", func(t *testing.T) {
+		mockRepo := new(MockProductRepo)
+		mockRepo.On("GetProduct", mock.Anything, "OLD-1").Return(inactive, nil)
+
+		_, err := NewCatalogService(mockRepo, nil).GetProduct(userCtx(), "OLD-1")
+
+		assert.ErrorIs(t, err, ErrProductNotFound)
+	})
+
+	t.Run("Inactive Visible To Admins", func(t *testing.T) {
+		mockRepo := new(MockProductRepo)
+		mockRepo.On("GetProduct", mock.Anything, "OLD-1").Return(inactive, nil)
+
+		product, err := NewCatalogService(mockRepo, nil).GetProduct(adminCtx(), "OLD-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Old", product.Name)
+	})
+}
+
+func TestCreateItemFromCatalog(t *testing.T) {
+	shoes := &model.Product{SKU: "SHO-1", Name: "Shoes", Price: usd("100.00"), Active: true}
+
+	t.Run("Resolves Name And Price", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockCatalog := new(MockProductRepo)
+		mockCatalog.On("GetProduct", mock.Anything, "SHO-1").Return(shoes, nil)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser}, nil)
+		mockRepo.On("CreateItem", mock.Anything, mock.MatchedBy(func(i model.CartItem) bool {
+			return i.SKU == "SHO-1" && i.Product == "Shoes" && i.Price == shoes.Price && i.Quantity == 2
+		})).Return(&model.CartItem{Id: 1, CartId: 1, SKU: "SHO-1", Product: "Shoes", Price: shoes.Price, Quantity: 2}, nil)
+
+		service := NewCartService(mockRepo, WithCatalog(mockCatalog))
+		item := model.CartItem{CartId: 1, SKU: "SHO-1", Product: "Cheap", Price: usd("0.01"), Quantity: 2}
+		created, err := service.CreateItem(userCtx(), item)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "100.00", created.Price.String())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown SKU", func(t *testing.T) {
+		mockCatalog := new(MockProductRepo)
+		mockCatalog.On("GetProduct", mock.Anything, "NOPE").Return(nil, nil)
+
+		service := NewCartService(new(MockCartRepo), WithCatalog(mockCatalog))
+		_, err := service.CreateItem(userCtx(), model.CartItem{CartId: 1, SKU: "NOPE"})
+
+		assert.ErrorIs(t, err, ErrUnknownProduct)
+	})
+
+	t.Run("Missing SKU", func(t *testing.T) {
+		service := NewCartService(new(MockCartRepo), WithCatalog(new(MockProductRepo)))
+		_, err := service.CreateItem(userCtx(), model.CartItem{CartId: 1, Product: "Shoes", Price: usd("1.00")})
+
+		assert.ErrorIs(t, err, ErrUnknownProduct)
+	})
+
+	t.Run("Inactive Product", func(t *testing.T) {
+		mockCatalog := new(MockProductRepo)
+		mockCatalog.On("GetProduct", mock.Anything, "SHO-1").Return(&model.Product{SKU: "SHO-1", Name: "Shoes", Price: usd("100.00")}, nil)
+
+		service := NewCartService(new(MockCartRepo), WithCatalog(mockCatalog))
+		_, err := service.CreateItem(userCtx(), model.CartItem{CartId: 1, SKU: "SHO-1"})
+
+		assert.ErrorIs(t, err, ErrProductInactive)
+	})
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var errNoOrderRepository = errors.New("checkout is not configured: no order repository")
+
+// BeginCheckout freezes the cart for checkout: its stock is reserved and it
+// moves to checking_out, where it no longer accepts changes. The returned
+// price is what Checkout will charge unless rates or rules change meanwhile.
+func (s *CartService) BeginCheckout(ctx context.Context, cartID int) (_ *model.Price, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.BeginCheckout", attribute.Int("cart.id", cartID))
+	defer func() { tracing.End(span, err) }()
+	var price *model.Price
+	err = s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		locked, err := s.lockOpenCart(ctx, cartID)
+		if err != nil {
+			return err
+		}
+		cart, err := s.freeze(ctx, locked)
+		if err != nil {
+			return err
+		}
+		price, err = s.price(ctx, cart)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// Checkout snapshots the cart's current price into a new order and marks the
+// cart as ordered. An open cart is frozen first, in the same transaction, so
+// BeginCheckout is optional. Nothing is persisted if any step fails.
+func (s *CartService) Checkout(ctx context.Context, cartID int) (_ *model.Order, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.Checkout", attribute.Int("cart.id", cartID))
+	defer func() { tracing.End(span, err) }()
+	if s.orders == nil {
+		return nil, errNoOrderRepository
+	}
+	var order *model.Order
+	var price *model.Price
+	err = s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		locked, err := s.lockCart(ctx, cartID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != model.CartOpen && locked.Status != model.CartCheckingOut {
+			return ErrCartNotOpen
+		}
+		if err = checkVersion(ctx, locked); err != nil {
+			return err
+		}
+		var cart *model.Cart
+		if locked.Status == model.CartOpen {
+			cart, err = s.freeze(ctx, locked)
+		} else {
+			// Reserve again: the reservations may have expired since
+			// BeginCheckout.
+			cart, err = s.reserveCart(ctx, cartID)
+		}
+		if err != nil {
+			return err
+		}
+		price, err = s.price(ctx, cart)
+		if err != nil {
+			return err
+		}
+		for _, coupon := range price.Coupons {
+			if err = s.coupons.RedeemCoupon(ctx, coupon.Code); err != nil {
+				return fmt.Errorf("failed to redeem coupon %s: %w", coupon.Code, err)
+			}
+		}
+		order, err = s.orders.CreateOrder(ctx, model.Order{
+			CartID:    cart.ID,
+			UserID:    locked.UserID,
+			SessionID: locked.SessionID,
+			Items:     cart.Items,
+			Price:     *price,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		if s.inventory != nil {
+			if err = s.inventory.CommitCart(ctx, cartID); err != nil {
+				return fmt.Errorf("failed to commit stock: %w", err)
+			}
+		}
+		return s.transition(ctx, locked, model.CartOrdered)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for range price.Discounts {
+		s.metrics.DiscountApplied(DiscountKindRule)
+	}
+	for range price.Coupons {
+		s.metrics.DiscountApplied(DiscountKindCoupon)
+	}
+	return order, nil
+}
+
+// CancelCheckout abandons a checkout started with BeginCheckout. The cart
+// becomes cancelled and its stock reservations are released.
+func (s *CartService) CancelCheckout(ctx context.Context, cartID int) (err error) {
+	ctx, span := tracing.Start(ctx, "CartService.CancelCheckout", attribute.Int("cart.id", cartID))
+	defer func() { tracing.End(span, err) }()
+	return s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		locked, err := s.lockCart(ctx, cartID)
+		if err != nil {
+			return err
+		}
+		if locked.Status == model.CartOpen {
+			return ErrCheckoutNotStarted
+		}
+		if locked.Status != model.CartCheckingOut {
+			return ErrCartNotOpen
+		}
+		if err = checkVersion(ctx, locked); err != nil {
+			return err
+		}
+		return s.transition(ctx, locked, model.CartCancelled)
+	})
+}
+
+// freeze reserves the stock of an open, non-empty cart and moves it to
+// checking_out.
+func (s *CartService) freeze(ctx context.Context, locked *model.Cart) (*model.Cart, error) {
+	cart, err := s.reserveCart(ctx, locked.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.transition(ctx, locked, model.CartCheckingOut); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+func (s *CartService) reserveCart(ctx context.Context, cartID int) (*model.Cart, error) {
+	cart, err := s.CartRepo.GetCart(ctx, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	if len(cart.Items) == 0 {
+		return nil, ErrCartEmpty
+	}
+	for _, item := range cart.Items {
+		if err = s.reserve(ctx, cartID, item.SKU, item.Quantity); err != nil {
+			return nil, err
+		}
+	}
+	return cart, nil
+}
+
+func (s *CartService) transition(ctx context.Context, cart *model.Cart, to model.CartStatus) error {
+	if !cart.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, cart.Status, to)
+	}
+	if err := s.CartRepo.UpdateCartStatus(ctx, cart.ID, cart.Status, to); err != nil {
+		return fmt.Errorf("failed to update cart status: %w", err)
+	}
+	cart.Status = to
+	if to == model.CartCancelled && s.inventory != nil {
+		if err := s.inventory.ReleaseCart(ctx, cart.ID); err != nil {
+			return fmt.Errorf("failed to release stock: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/repository/Coupon"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOrderRepo struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepo) CreateOrder(ctx context.Context, order model.Order) (*model.Order, error) {
+	args := m.Called(ctx, order)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Order), args.Error(1)
+}
+
+func TestCheckout(t *testing.T) {
+	items := []model.CartItem{{Id: 1, CartId: 1, Product: "Shoes", Price: usd("100.00"), Quantity: 2}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockOrders := new(MockOrderRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartOpen, model.CartCheckingOut).Return(nil)
+		mockOrders.On("CreateOrder", mock.Anything, mock.MatchedBy(func(o model.Order) bool {
+			return o.CartID == 1 && o.UserID == testUser && o.Price.FinalPrice.String() == "200.00" && len(o.Items) == 1
+		})).Return(&model.Order{ID: 42, CartID: 1}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartCheckingOut, model.CartOrdered).Return(nil)
+
+		service := NewCartService(mockRepo, WithOrders(mockOrders))
+		order, err := service.Checkout(userCtx(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, order.ID)
+		mockRepo.AssertExpectations(t)
+		mockOrders.AssertExpectations(t)
+	})
+
+	t.Run("Redeems Coupons", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockOrders := new(MockOrderRepo)
+		mockCoupons := new(MockCouponRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartOpen, model.CartCheckingOut).Return(nil)
+		mockCoupons.On("ListCartCoupons", mock.Anything, 1).Return([]model.Coupon{{Code: "SAVE10", Percent: 10, Active: true}}, nil)
+		mockCoupons.On("RedeemCoupon", mock.Anything, "SAVE10").Return(nil)
+		mockOrders.On("CreateOrder", mock.Anything, mock.MatchedBy(func(o model.Order) bool {
+			return o.Price.FinalPrice.String() == "180.00"
+		})).Return(&model.Order{ID: 42, CartID: 1}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartCheckingOut, model.CartOrdered).Return(nil)
+
+		service := NewCartService(mockRepo, WithOrders(mockOrders), WithCoupons(mockCoupons))
+		_, err := service.Checkout(userCtx(), 1)
+
+		assert.NoError(t, err)
+		mockCoupons.AssertExpectations(t)
+		mockOrders.AssertExpectations(t)
+	})
+
+	t.Run("Coupon Usage Limit Reached", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockOrders := new(MockOrderRepo)
+		mockCoupons := new(MockCouponRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartOpen, model.CartCheckingOut).Return(nil)
+		mockCoupons.On("ListCartCoupons", mock.Anything, 1).Return([]model.Coupon{{Code: "SAVE10", Percent: 10, Active: true}}, nil)
+		mockCoupons.On("RedeemCoupon", mock.Anything, "SAVE10").Return(Coupon.ErrUsageLimitReached)
+
+		service := NewCartService(mockRepo, WithOrders(mockOrders), WithCoupons(mockCoupons))
+		order, err := service.Checkout(userCtx(), 1)
+
+		assert.ErrorIs(t, err, Coupon.ErrUsageLimitReached)
+		assert.Nil(t, order)
+		mockOrders.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not Open", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOrdered}, nil)
+
+		service := NewCartService(mockRepo, WithOrders(new(MockOrderRepo)))
+		order, err := service.Checkout(userCtx(), 1)
+
+		assert.ErrorIs(t, err, ErrCartNotOpen)
+		assert.Nil(t, order)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Empty Cart", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser}, nil)
+
+		service := NewCartService(mockRepo, WithOrders(new(MockOrderRepo)))
+		order, err := service.Checkout(userCtx(), 1)
+
+		assert.ErrorIs(t, err, ErrCartEmpty)
+		assert.Nil(t, order)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Order Failure", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockOrders := new(MockOrderRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartOpen, model.CartCheckingOut).Return(nil)
+		mockOrders.On("CreateOrder", mock.Anything, mock.Anything).Return(nil, errors.New("insert failed"))
+
+		service := NewCartService(mockRepo, WithOrders(mockOrders))
+		order, err := service.Checkout(userCtx(), 1)
+
+		assert.Error(t, err)
+		assert.Nil(t, order)
+		mockRepo.AssertExpectations(t)
+		mockOrders.AssertExpectations(t)
+	})
+}
+
+func TestBeginCheckout(t *testing.T) {
+	items := []model.CartItem{{Id: 1, CartId: 1, Product: "Shoes", Price: usd("100.00"), Quantity: 2}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		locked := &model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}
+		mockRepo.On("LockCart", mock.Anything, 1).Return(locked, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartOpen, model.CartCheckingOut).Return(nil)
+
+		service := NewCartService(mockRepo)
+		price, err := service.BeginCheckout(userCtx(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "200.00", price.FinalPrice.String())
+		assert.Equal(t, model.CartCheckingOut, locked.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Already Checking Out", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartCheckingOut}, nil)
+
+		service := NewCartService(mockRepo)
+		_, err := service.BeginCheckout(userCtx(), 1)
+
+		assert.ErrorIs(t, err, ErrCartNotOpen)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Then Checkout", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockOrders := new(MockOrderRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartCheckingOut}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+		mockOrders.On("CreateOrder", mock.Anything, mock.Anything).Return(&model.Order{ID: 42, CartID: 1}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartCheckingOut, model.CartOrdered).Return(nil)
+
+		service := NewCartService(mockRepo, WithOrders(mockOrders))
+		order, err := service.Checkout(userCtx(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, order.ID)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "UpdateCartStatus", mock.Anything, 1, model.CartOpen, model.CartCheckingOut)
+	})
+}
+
+func TestCancelCheckout(t *testing.T) {
+	tests := []struct {
+		status      model.CartStatus
+		expectedErr error
+	}{
+		{status: model.CartCheckingOut},
+		{status: model.CartOpen, expectedErr: ErrCheckoutNotStarted},
+		{status: model.CartOrdered, expectedErr: ErrCartNotOpen},
+		{status: model.CartCancelled, expectedErr: ErrCartNotOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			mockRepo := new(MockCartRepo)
+			mockInventory := new(MockInventoryRepo)
+			mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: tt.status}, nil)
+			if tt.expectedErr == nil {
+				mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartCheckingOut, model.CartCancelled).Return(nil)
+				mockInventory.On("ReleaseCart", mock.Anything, 1).Return(nil)
+			}
+
+			service := NewCartService(mockRepo, WithInventory(mockInventory, time.Minute))
+			err := service.CancelCheckout(userCtx(), 1)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			mockInventory.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartChangesRejectedWhenNotOpen(t *testing.T) {
+	for _, status := range []model.CartStatus{model.CartCheckingOut, model.CartOrdered, model.CartCancelled} {
+		t.Run(string(status), func(t *testing.T) {
+			mockRepo := new(MockCartRepo)
+			mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: status}, nil)
+
+			service := NewCartService(mockRepo)
+			_, createErr := service.CreateItem(userCtx(), model.CartItem{CartId: 1, Product: "Apple", Price: usd("1.00")})
+			deleteErr := service.DeleteItem(userCtx(), model.CartItem{Id: 1, CartId: 1})
+
+			assert.ErrorIs(t, createErr, ErrCartNotOpen)
+			assert.ErrorIs(t, deleteErr, ErrCartNotOpen)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to model.CartStatus
+		allowed  bool
+	}{
+		{from: model.CartOpen, to: model.CartCheckingOut, allowed: true},
+		{from: model.CartCheckingOut, to: model.CartOrdered, allowed: true},
+		{from: model.CartCheckingOut, to: model.CartCancelled, allowed: true},
+		{from: model.CartOpen, to: model.CartOrdered},
+		{from: model.CartOrdered, to: model.CartOpen},
+		{from: model.CartCancelled, to: model.CartCheckingOut},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
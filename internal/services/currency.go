@@ -0,0 +1,142 @@
+package services
+
+import (
+	"cart-api/internal/exchange"
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type ExchangeRateRepository interface {
+	GetExchangeRate(context.Context, string, string) (*model.ExchangeRate, error)
+}
+
+func WithExchangeRates(repo ExchangeRateRepository) Option {
+	return func(s *CartService) {
+		s.exchangeRates = repo
+	}
+}
+
+type displayCurrencyKey struct{}
+
+// WithDisplayCurrency makes GetPrice convert the cart totals into currency.
+func WithDisplayCurrency(ctx context.Context, currency string) context.Context {
+	return context.WithValue(ctx, displayCurrencyKey{}, strings.ToUpper(strings.TrimSpace(currency)))
+}
+
+func DisplayCurrency(ctx context.Context) (string, bool) {
+	currency, ok := ctx.Value(displayCurrencyKey{}).(string)
+	return currency, ok && currency != ""
+}
+
+// cartCurrency is the currency every item of the cart is priced in. Carts
+// take the currency of their first item; it is empty for carts that never
+// held one.
+func cartCurrency(cart *model.Cart) string {
+	if cart.Currency != "" {
+		return cart.Currency
+	}
+	if len(cart.Items) > 0 {
+		return cart.Items[0].Price.Currency
+	}
+	return ""
+}
+
+// exchangeRate finds the rate from base to quote, inverting the opposite
+// pair when only that one is loaded.
+func (s *CartService) exchangeRate(ctx context.Context, base, quote string) (*model.ExchangeRate, error) {
+	if s.exchangeRates == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrUnknownExchangeRate, base, quote)
+	}
+	rate, err := s.exchangeRates.GetExchangeRate(ctx, base, quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	if rate != nil {
+		return rate, nil
+	}
+	rate, err = s.exchangeRates.GetExchangeRate(ctx, quote, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	if rate == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrUnknownExchangeRate, base, quote)
+	}
+	inverse := exchange.Invert(*rate)
+	return &inverse, nil
+}
+
+// configuredIn returns the converter of configured amounts such as discount
+// thresholds, shipping prices and limits, which are in money.DefaultCurrency,
+// into currency. Without an exchange rate it only accepts amounts already in
+// currency, so features priced in the default currency are off for the cart.
+func (s *CartService) configuredIn(ctx context.Context, currency string) (exchange.Converter, error) {
+	if currency == money.DefaultCurrency {
+		return exchange.NewConverter(currency, nil, s.rounding), nil
+	}
+	rate, err := s.exchangeRate(ctx, money.DefaultCurrency, currency)
+	switch {
+	case errors.Is(err, ErrUnknownExchangeRate):
+		return exchange.NewConverter(currency, nil, s.rounding), nil
+	case err != nil:
+		return nil, err
+	}
+	return exchange.NewConverter(currency, rate, s.rounding), nil
+}
+
+// convertPrice converts every amount of price into currency. Each amount is
+// rounded on its own, so converted totals may differ from the sum of their
+// parts by a cent.
+func (s *CartService) convertPrice(ctx context.Context, price *model.Price, currency string) (*model.Price, error) {
+	if !money.ValidCurrency(currency) {
+		return nil, ErrInvalidCurrency
+	}
+	from := price.TotalPrice.Currency
+	if from == currency {
+		return price, nil
+	}
+	rate, err := s.exchangeRate(ctx, from, currency)
+	if err != nil {
+		return nil, err
+	}
+	convert := func(m money.Money) money.Money {
+		return exchange.Convert(m, *rate, s.rounding)
+	}
+	converted := *price
+	converted.TotalPrice = convert(price.TotalPrice)
+	converted.DiscountAmount = convert(price.DiscountAmount)
+	converted.FinalPrice = convert(price.FinalPrice)
+	converted.Subtotal = convert(price.Subtotal)
+	converted.TaxTotal = convert(price.TaxTotal)
+	converted.GrandTotal = convert(price.GrandTotal)
+	converted.Discounts = make([]model.AppliedDiscount, 0, len(price.Discounts))
+	for _, discount := range price.Discounts {
+		converted.Discounts = append(converted.Discounts, model.AppliedDiscount{Rule: discount.Rule, Amount: convert(discount.Amount)})
+	}
+	converted.Coupons = make([]model.AppliedCoupon, 0, len(price.Coupons))
+	for _, coupon := range price.Coupons {
+		converted.Coupons = append(converted.Coupons, model.AppliedCoupon{Code: coupon.Code, Amount: convert(coupon.Amount)})
+	}
+	converted.Taxes = make([]model.TaxLine, 0, len(price.Taxes))
+	for _, tax := range price.Taxes {
+		converted.Taxes = append(converted.Taxes, model.TaxLine{
+			Category: tax.Category,
+			Rate:     tax.Rate,
+			Taxable:  convert(tax.Taxable),
+			Amount:   convert(tax.Amount),
+		})
+	}
+	if price.Shipping != nil {
+		converted.Shipping = &model.AppliedShipping{Method: price.Shipping.Method, Amount: convert(price.Shipping.Amount)}
+	}
+	converted.Exchange = &model.AppliedExchangeRate{
+		From:      rate.Base,
+		To:        rate.Quote,
+		Rate:      rate.Rate,
+		UpdatedAt: rate.UpdatedAt,
+	}
+	return &converted, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"cart-api/internal/auth"
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockExchangeRateRepo struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepo) GetExchangeRate(ctx context.Context, base, quote string) (*model.ExchangeRate, error) {
+	args := m.Called(ctx, base, quote)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ExchangeRate), args.Error(1)
+}
+
+func TestGetPriceInCurrency(t *testing.T) {
+	updatedAt := time.Date(2026, 4, 12, 16, 0, 0, 0, time.UTC)
+	cart := &model.Cart{ID: 1, UserID: testUser, Currency: "USD", Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Shoes", Price: usd("100.00"), Quantity: 1},
+	}}
+
+	t.Run("Direct Rate", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRates := new(MockExchangeRateRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRates.On("GetExchangeRate", mock.Anything, "USD", "EUR").
+			Return(&model.ExchangeRate{Base: "USD", Quote: "EUR", Rate: 92_000_000, UpdatedAt: updatedAt}, nil)
+
+		service := NewCartService(mockRepo, WithExchangeRates(mockRates))
+		price, err := service.GetPrice(WithDisplayCurrency(userCtx(), "eur"), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "EUR", price.FinalPrice.Currency)
+		assert.Equal(t, "92.00", price.FinalPrice.String())
+		assert.Equal(t, "92.00", price.GrandTotal.String())
+		assert.Equal(t, &model.AppliedExchangeRate{From: "USD", To: "EUR", Rate: 92_000_000, UpdatedAt: updatedAt}, price.Exchange)
+	})
+
+	t.Run("Inverse Rate", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRates := new(MockExchangeRateRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRates.On("GetExchangeRate", mock.Anything, "USD", "GBP").Return(nil, nil)
+		mockRates.On("GetExchangeRate", mock.Anything, "GBP", "USD").
+			Return(&model.ExchangeRate{Base: "GBP", Quote: "USD", Rate: 125_000_000, UpdatedAt: updatedAt}, nil)
+
+		service := NewCartService(mockRepo, WithExchangeRates(mockRates))
+		price, err := service.GetPrice(WithDisplayCurrency(userCtx(), "GBP"), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "80.00", price.FinalPrice.String())
+		assert.Equal(t, int64(80_000_000), price.Exchange.Rate)
+		assert.Equal(t, "USD", price.Exchange.From)
+	})
+
+	t.Run("Same Currency", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+
+		service := NewCartService(mockRepo, WithExchangeRates(new(MockExchangeRateRepo)))
+		price, err := service.GetPrice(WithDisplayCurrency(userCtx(), "USD"), 1)
+
+		require.NoError(t, err)
+		assert.Nil(t, price.Exchange)
+		assert.Equal(t, "100.00", price.FinalPrice.String())
+	})
+
+	t.Run("Unknown Rate", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRates := new(MockExchangeRateRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRates.On("GetExchangeRate", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+		service := NewCartService(mockRepo, WithExchangeRates(mockRates))
+		_, err := service.GetPrice(WithDisplayCurrency(userCtx(), "CHF"), 1)
+
+		assert.ErrorIs(t, err, ErrUnknownExchangeRate)
+	})
+
+	t.Run("Invalid Currency", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+
+		service := NewCartService(mockRepo)
+		_, err := service.GetPrice(WithDisplayCurrency(userCtx(), "euro"), 1)
+
+		assert.ErrorIs(t, err, ErrInvalidCurrency)
+	})
+}
+
+func TestGetPriceConvertsConfiguredThresholds(t *testing.T) {
+	cart := &model.Cart{ID: 1, UserID: testUser, Currency: "JPY", Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Camera", Price: money.MustParse("800000", "JPY"), Quantity: 1},
+	}}
+
+	t.Run("With Rate", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRates := new(MockExchangeRateRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRates.On("GetExchangeRate", mock.Anything, "USD", "JPY").
+			Return(&model.ExchangeRate{Base: "USD", Quote: "JPY", Rate: 15_000_000_000}, nil)
+
+		service := NewCartService(mockRepo, WithExchangeRates(mockRates))
+		price, err := service.GetPrice(userCtx(), 1)
+
+		require.NoError(t, err)
+		require.Len(t, price.Discounts, 1)
+		assert.Equal(t, "total_over_5000", price.Discounts[0].Rule)
+		assert.Equal(t, "720000", price.FinalPrice.String())
+	})
+
+	t.Run("Without Rate", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+
+		service := NewCartService(mockRepo)
+		price, err := service.GetPrice(userCtx(), 1)
+
+		require.NoError(t, err)
+		assert.Empty(t, price.Discounts, "5000 must not be read as yen")
+		assert.Equal(t, "800000", price.FinalPrice.String())
+	})
+}
+
+func TestCreateItemCurrencyMismatch(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	item := model.CartItem{CartId: 1, Product: "Croissant", Price: money.MustParse("2.50", "EUR"), Quantity: 1}
+
+	mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+	mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Currency: "USD"}, nil)
+
+	service := NewCartService(mockRepo)
+	_, err := service.CreateItem(userCtx(), item)
+
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	mockRepo.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+}
+
+func TestMergeCartCurrencyMismatch(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	target := &model.Cart{ID: 1, UserID: testUser, Currency: "USD", Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Apple", Price: usd("10.00"), Quantity: 1},
+	}}
+	guest := &model.Cart{ID: 2, SessionID: "sess-1", Currency: "EUR", Items: []model.CartItem{
+		{Id: 7, CartId: 2, Product: "Kiwi", Price: money.MustParse("3.00", "EUR"), Quantity: 4},
+	}}
+
+	mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+	mockRepo.On("LockCart", mock.Anything, 2).Return(&model.Cart{ID: 2, SessionID: "sess-1", Status: model.CartOpen}, nil)
+	mockRepo.On("GetCart", mock.Anything, 1).Return(target, nil)
+	mockRepo.On("GetCart", mock.Anything, 2).Return(guest, nil)
+	mockRepo.On("DeleteCart", mock.Anything, 2).Return(nil)
+
+	service := NewCartService(mockRepo)
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: testUser, SessionID: "sess-1"})
+	result, err := service.MergeCart(ctx, 1, 2)
+
+	require.NoError(t, err)
+	require.Len(t, result.Conflicts, 1)
+	assert.Equal(t, "Kiwi", result.Conflicts[0].Product)
+	assert.Equal(t, ErrCurrencyMismatch.Error(), result.Conflicts[0].Reason)
+	mockRepo.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+}
@@ -5,9 +5,48 @@ import (
 )
 
 var (
-	ErrCartNotFound   = errors.New("cart not found")
-	ErrItemNotFound   = errors.New("item not found")
-	ErrInvalidProduct = errors.New("product name cannot be blank")
-	ErrInvalidPrice   = errors.New("incorrect price information")
-	ErrReachCartLimit = errors.New("cart limit reached: max 5 distinct products")
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("access to the cart is forbidden")
+	ErrAdminRequired   = errors.New("administrator access required")
+	ErrCartNotFound    = errors.New("cart not found")
+	ErrItemNotFound    = errors.New("item not found")
+	ErrInvalidProduct  = errors.New("product name cannot be blank")
+	ErrInvalidPrice    = errors.New("incorrect price information")
+	ErrInvalidQuantity = errors.New("quantity must be a positive number")
+	ErrInvalidCurrency = errors.New("currency must be a 3-letter ISO 4217 code")
+	ErrReachCartLimit  = errors.New("cart limit reached")
+
+	ErrCartNotOpen        = errors.New("cart is no longer open for changes")
+	ErrInvalidTransition  = errors.New("illegal cart status transition")
+	ErrCartEmpty          = errors.New("cannot check out an empty cart")
+	ErrCheckoutNotStarted = errors.New("checkout has not been started")
+	ErrVersionMismatch    = errors.New("cart was modified by another request")
+
+	ErrProductNotFound = errors.New("product not found")
+	ErrUnknownProduct  = errors.New("unknown product SKU")
+	ErrProductInactive = errors.New("product is not available")
+	ErrInvalidSKU      = errors.New("SKU must be 1-64 letters, digits, '-', '_' or '.'")
+
+	ErrCurrencyMismatch    = errors.New("item currency does not match the cart currency")
+	ErrUnknownExchangeRate = errors.New("no exchange rate for currency pair")
+
+	ErrUnknownTaxRegion   = errors.New("no tax rates configured for region")
+	ErrInvalidTaxCategory = errors.New("tax category must be 1-32 lowercase letters, digits or '_'")
+
+	ErrUnknownShippingMethod = errors.New("unknown shipping method")
+	ErrShippingUnavailable   = errors.New("shipping method is not available for this cart")
+	ErrInvalidDimensions     = errors.New("weight and dimensions must not be negative")
+
+	ErrMergeSameCart    = errors.New("cannot merge a cart into itself")
+	ErrMergeNotGuest    = errors.New("only guest carts can be merged")
+	ErrMergeTargetGuest = errors.New("carts can only be merged into a signed-in user's cart")
+
+	ErrCouponNotFound       = errors.New("coupon not found")
+	ErrCouponNotActive      = errors.New("coupon is not active")
+	ErrCouponExpired        = errors.New("coupon has expired")
+	ErrCouponUsageLimit     = errors.New("coupon usage limit reached")
+	ErrCouponMinTotal       = errors.New("cart total is below the coupon minimum")
+	ErrCouponCurrency       = errors.New("coupon is not valid for the cart's currency")
+	ErrCouponAlreadyApplied = errors.New("coupon is already applied to the cart")
+	ErrCouponNotStackable   = errors.New("coupon cannot be combined with coupons already applied to the cart")
 )
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultReservationTTL = 15 * time.Minute
+
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// InsufficientStockError reports how much of a SKU is still available when a
+// reservation for Requested units cannot be made.
+type InsufficientStockError struct {
+	SKU       string
+	Requested int
+	Available int
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for %s: requested %d, available %d", e.SKU, e.Requested, e.Available)
+}
+
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
+
+type InventoryRepository interface {
+	LockStock(context.Context, string, int) (*model.Stock, error)
+	Reserve(context.Context, model.Reservation) error
+	Release(context.Context, int, string) error
+	ReleaseCart(context.Context, int) error
+	CommitCart(context.Context, int) error
+}
+
+// WithInventory reserves stock for every cart line with a SKU. Reservations
+// stop counting against stock once they are older than ttl.
+func WithInventory(repo InventoryRepository, ttl time.Duration) Option {
+	return func(s *CartService) {
+		s.inventory = repo
+		if ttl > 0 {
+			s.reservationTTL = ttl
+		}
+	}
+}
+
+// reserve sets the cart's reservation for sku to quantity units. SKUs without
+// an inventory row are not stock-tracked and are always accepted.
+func (s *CartService) reserve(ctx context.Context, cartID int, sku string, quantity int) error {
+	if s.inventory == nil || sku == "" {
+		return nil
+	}
+	stock, err := s.inventory.LockStock(ctx, sku, cartID)
+	if err != nil {
+		return fmt.Errorf("failed to lock stock: %w", err)
+	}
+	if stock == nil {
+		return nil
+	}
+	if available := stock.Available(); quantity > available {
+		return &InsufficientStockError{SKU: sku, Requested: quantity, Available: available}
+	}
+	return s.inventory.Reserve(ctx, model.Reservation{
+		CartID:    cartID,
+		SKU:       sku,
+		Quantity:  quantity,
+		ExpiresAt: s.now().Add(s.reservationTTL),
+	})
+}
+
+func (s *CartService) release(ctx context.Context, cartID int, sku string) error {
+	if s.inventory == nil || sku == "" {
+		return nil
+	}
+	return s.inventory.Release(ctx, cartID, sku)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInventoryRepo struct {
+	mock.Mock
+}
+
+func (m *MockInventoryRepo) LockStock(ctx context.Context, sku string, cartID int) (*model.Stock, error) {
+	args := m.Called(ctx, sku, cartID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Stock), args.Error(1)
+}
+
+func (m *MockInventoryRepo) Reserve(ctx context.Context, res model.Reservation) error {
+	return m.Called(ctx, res).Error(0)
+}
+
+func (m *MockInventoryRepo) Release(ctx context.Context, cartID int, sku string) error {
+	return m.Called(ctx, cartID, sku).Error(0)
+}
+
+func (m *MockInventoryRepo) ReleaseCart(ctx context.Context, cartID int) error {
+	return m.Called(ctx, cartID).Error(0)
+}
+
+func (m *MockInventoryRepo) CommitCart(ctx context.Context, cartID int) error {
+	return m.Called(ctx, cartID).Error(0)
+}
+
+func TestCreateItemReservesStock(t *testing.T) {
+	now := time.Date(2026, 3, 23, 9, 0, 0, 0, time.UTC)
+	openCart := &model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}
+	inCart := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+		{Id: 3, CartId: 1, SKU: "SHO-1", Product: "Shoes", Price: usd("10.00"), Quantity: 2},
+	}}
+	item := model.CartItem{CartId: 1, SKU: "SHO-1", Product: "Shoes", Price: usd("10.00"), Quantity: 3}
+
+	t.Run("Reserves Line Total", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockInventory := new(MockInventoryRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(openCart, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(inCart, nil)
+		mockInventory.On("LockStock", mock.Anything, "SHO-1", 1).Return(&model.Stock{SKU: "SHO-1", OnHand: 10, Reserved: 4}, nil)
+		mockInventory.On("Reserve", mock.Anything, model.Reservation{
+			CartID: 1, SKU: "SHO-1", Quantity: 5, ExpiresAt: now.Add(10 * time.Minute),
+		}).Return(nil)
+		mockRepo.On("CreateItem", mock.Anything, item).Return(&model.CartItem{Id: 3, CartId: 1, SKU: "SHO-1", Quantity: 5}, nil)
+
+		service := NewCartService(mockRepo, WithInventory(mockInventory, 10*time.Minute), WithClock(func() time.Time { return now }))
+		created, err := service.CreateItem(userCtx(), item)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, created.Quantity)
+		mockRepo.AssertExpectations(t)
+		mockInventory.AssertExpectations(t)
+	})
+
+	t.Run("Insufficient Stock", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockInventory := new(MockInventoryRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(openCart, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(inCart, nil)
+		mockInventory.On("LockStock", mock.Anything, "SHO-1", 1).Return(&model.Stock{SKU: "SHO-1", OnHand: 10, Reserved: 6}, nil)
+
+		service := NewCartService(mockRepo, WithInventory(mockInventory, time.Minute))
+		_, err := service.CreateItem(userCtx(), item)
+
+		var stockErr *InsufficientStockError
+		assert.ErrorAs(t, err, &stockErr)
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+		assert.Equal(t, 4, stockErr.Available)
+		assert.Equal(t, 5, stockErr.Requested)
+		mockRepo.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+		mockInventory.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Untracked SKU", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockInventory := new(MockInventoryRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(openCart, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(inCart, nil)
+		mockInventory.On("LockStock", mock.Anything, "SHO-1", 1).Return(nil, nil)
+		mockRepo.On("CreateItem", mock.Anything, item).Return(&model.CartItem{Id: 3, CartId: 1, Quantity: 5}, nil)
+
+		service := NewCartService(mockRepo, WithInventory(mockInventory, time.Minute))
+		_, err := service.CreateItem(userCtx(), item)
+
+		assert.NoError(t, err)
+		mockInventory.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteItemReleasesStock(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	mockInventory := new(MockInventoryRepo)
+	item := model.CartItem{Id: 3, CartId: 1}
+	mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+	mockRepo.On("ItemExists", mock.Anything, 3).Return(true, nil)
+	mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+		{Id: 3, CartId: 1, SKU: "SHO-1", Quantity: 2},
+	}}, nil)
+	mockInventory.On("Release", mock.Anything, 1, "SHO-1").Return(nil)
+	mockRepo.On("DeleteItem", mock.Anything, item).Return(nil)
+
+	service := NewCartService(mockRepo, WithInventory(mockInventory, time.Minute))
+
+	assert.NoError(t, service.DeleteItem(userCtx(), item))
+	mockRepo.AssertExpectations(t)
+	mockInventory.AssertExpectations(t)
+}
+
+func TestCheckoutCommitsStock(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	mockOrders := new(MockOrderRepo)
+	mockInventory := new(MockInventoryRepo)
+	items := []model.CartItem{{Id: 1, CartId: 1, SKU: "SHO-1", Product: "Shoes", Price: usd("100.00"), Quantity: 2}}
+	mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+	mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+	mockInventory.On("LockStock", mock.Anything, "SHO-1", 1).Return(&model.Stock{SKU: "SHO-1", OnHand: 2}, nil)
+	mockInventory.On("Reserve", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartOpen, model.CartCheckingOut).Return(nil)
+	mockOrders.On("CreateOrder", mock.Anything, mock.Anything).Return(&model.Order{ID: 42, CartID: 1}, nil)
+	mockInventory.On("CommitCart", mock.Anything, 1).Return(nil)
+	mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartCheckingOut, model.CartOrdered).Return(nil)
+
+	service := NewCartService(mockRepo, WithOrders(mockOrders), WithInventory(mockInventory, time.Minute))
+	_, err := service.Checkout(userCtx(), 1)
+
+	assert.NoError(t, err)
+	mockInventory.AssertExpectations(t)
+}
+
+// memoryInventory keeps stock and reservations in maps so a test can observe
+// how much stock a cart holds.
+type memoryInventory struct {
+	onHand       map[string]int
+	reservations map[int]map[string]int
+}
+
+func newMemoryInventory(onHand map[string]int) *memoryInventory {
+	return &memoryInventory{onHand: onHand, reservations: map[int]map[string]int{}}
+}
+
+func (m *memoryInventory) LockStock(_ context.Context, sku string, cartID int) (*model.Stock, error) {
+	onHand, ok := m.onHand[sku]
+	if !ok {
+		return nil, nil
+	}
+	stock := &model.Stock{SKU: sku, OnHand: onHand}
+	for id, held := range m.reservations {
+		if id != cartID {
+			stock.Reserved += held[sku]
+		}
+	}
+	return stock, nil
+}
+
+func (m *memoryInventory) Reserve(_ context.Context, res model.Reservation) error {
+	if m.reservations[res.CartID] == nil {
+		m.reservations[res.CartID] = map[string]int{}
+	}
+	m.reservations[res.CartID][res.SKU] = res.Quantity
+	return nil
+}
+
+func (m *memoryInventory) Release(_ context.Context, cartID int, sku string) error {
+	delete(m.reservations[cartID], sku)
+	return nil
+}
+
+func (m *memoryInventory) ReleaseCart(_ context.Context, cartID int) error {
+	delete(m.reservations, cartID)
+	return nil
+}
+
+func (m *memoryInventory) CommitCart(_ context.Context, cartID int) error {
+	for sku, quantity := range m.reservations[cartID] {
+		m.onHand[sku] -= quantity
+	}
+	delete(m.reservations, cartID)
+	return nil
+}
+
+func (m *memoryInventory) available(sku string) int {
+	stock, _ := m.LockStock(context.Background(), sku, 0)
+	return stock.Available()
+}
+
+func TestCancelCheckoutReleasesStock(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	inventory := newMemoryInventory(map[string]int{"SHO-1": 5})
+	locked := &model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}
+	items := []model.CartItem{{Id: 1, CartId: 1, SKU: "SHO-1", Product: "Shoes", Price: usd("100.00"), Quantity: 2}}
+	mockRepo.On("LockCart", mock.Anything, 1).Return(locked, nil)
+	mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+	mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartOpen, model.CartCheckingOut).Return(nil)
+	mockRepo.On("UpdateCartStatus", mock.Anything, 1, model.CartCheckingOut, model.CartCancelled).Return(nil)
+	service := NewCartService(mockRepo, WithOrders(new(MockOrderRepo)), WithInventory(inventory, time.Minute))
+
+	_, err := service.BeginCheckout(userCtx(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inventory.available("SHO-1"))
+
+	assert.NoError(t, service.CancelCheckout(userCtx(), 1))
+	assert.Equal(t, model.CartCancelled, locked.Status)
+	assert.Equal(t, 5, inventory.available("SHO-1"))
+
+	_, err = service.Checkout(userCtx(), 1)
+	assert.ErrorIs(t, err, ErrCartNotOpen)
+	assert.Equal(t, 5, inventory.available("SHO-1"))
+	mockRepo.AssertExpectations(t)
+}
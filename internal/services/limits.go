@@ -0,0 +1,196 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	LimitDistinctProducts = "max_distinct_products"
+	LimitUnits            = "max_units"
+	LimitCartValue        = "max_cart_value"
+	LimitItemPrice        = "max_item_price"
+	LimitNameLength       = "product_name_length"
+	LimitNameCharset      = "product_name_charset"
+)
+
+// Limits are the business limits enforced on carts. A zero value disables a
+// limit; money limits are in money.DefaultCurrency and converted into the
+// currency of the cart.
+type Limits struct {
+	MaxDistinctProducts int
+	MaxUnits            int
+	MaxCartValue        money.Money
+	MaxItemPrice        money.Money
+	MaxNameLength       int
+	NamePattern         string
+}
+
+func DefaultLimits() Limits {
+	return Limits{MaxDistinctProducts: 5, MaxNameLength: 255}
+}
+
+func (l Limits) Validate() error {
+	if l.MaxDistinctProducts < 0 || l.MaxUnits < 0 || l.MaxNameLength < 0 ||
+		l.MaxCartValue.IsNegative() || l.MaxItemPrice.IsNegative() {
+		return fmt.Errorf("cart limits must not be negative")
+	}
+	if _, err := regexp.Compile(l.NamePattern); err != nil {
+		return fmt.Errorf("invalid product name pattern: %w", err)
+	}
+	return nil
+}
+
+// WithLimits replaces DefaultLimits. The limits must pass Validate.
+func WithLimits(limits Limits) Option {
+	return func(s *CartService) {
+		limits.MaxCartValue.Currency = money.DefaultCurrency
+		limits.MaxItemPrice.Currency = money.DefaultCurrency
+		s.limits = limits
+		s.namePattern = nil
+		if limits.NamePattern != "" {
+			s.namePattern = regexp.MustCompile(limits.NamePattern)
+		}
+	}
+}
+
+// LimitError reports the limit a change would exceed. Max and Actual are
+// decimal numbers; Pattern is set for charset violations instead.
+type LimitError struct {
+	Limit   string
+	Max     string
+	Actual  string
+	Pattern string
+}
+
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case LimitDistinctProducts:
+		return fmt.Sprintf("cart limit reached: max %s distinct products", e.Max)
+	case LimitUnits:
+		return fmt.Sprintf("cart limit reached: max %s units", e.Max)
+	case LimitCartValue:
+		return fmt.Sprintf("cart limit reached: max cart value %s", e.Max)
+	case LimitItemPrice:
+		return fmt.Sprintf("item price %s exceeds the maximum of %s", e.Actual, e.Max)
+	case LimitNameLength:
+		return fmt.Sprintf("product name must be at most %s characters", e.Max)
+	case LimitNameCharset:
+		return fmt.Sprintf("product name must match %s", e.Pattern)
+	}
+	return fmt.Sprintf("limit %s exceeded", e.Limit)
+}
+
+// Is lets callers match name and price limits as invalid input and the
+// other limits as ErrReachCartLimit.
+func (e *LimitError) Is(target error) bool {
+	switch e.Limit {
+	case LimitNameLength, LimitNameCharset:
+		return target == ErrInvalidProduct
+	case LimitItemPrice:
+		return target == ErrInvalidPrice
+	}
+	return target == ErrReachCartLimit
+}
+
+func (s *CartService) checkItemLimits(ctx context.Context, item model.CartItem) error {
+	if max := s.limits.MaxNameLength; max > 0 {
+		if length := utf8.RuneCountInString(item.Product); length > max {
+			return &LimitError{Limit: LimitNameLength, Max: strconv.Itoa(max), Actual: strconv.Itoa(length)}
+		}
+	}
+	if s.namePattern != nil && !s.namePattern.MatchString(item.Product) {
+		return &LimitError{Limit: LimitNameCharset, Pattern: s.limits.NamePattern}
+	}
+	if !s.limits.MaxItemPrice.IsZero() {
+		max, err := s.limitIn(ctx, s.limits.MaxItemPrice, item.Price.Currency)
+		if err != nil {
+			return err
+		}
+		if item.Price.Amount > max.Amount {
+			return &LimitError{Limit: LimitItemPrice, Max: max.String(), Actual: item.Price.String()}
+		}
+	}
+	return nil
+}
+
+// limitIn converts a money limit into currency. Limits fail closed: without
+// an exchange rate the change is rejected rather than checked against the
+// wrong amount.
+func (s *CartService) limitIn(ctx context.Context, limit money.Money, currency string) (money.Money, error) {
+	convert, err := s.configuredIn(ctx, currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	converted, ok := convert(limit)
+	if !ok {
+		return money.Money{}, fmt.Errorf("%w: %s/%s", ErrUnknownExchangeRate, limit.Currency, currency)
+	}
+	return converted, nil
+}
+
+type cartTotals struct {
+	products int
+	units    int
+	value    int64
+}
+
+func totalsOf(items []model.CartItem) cartTotals {
+	products := make(map[string]struct{}, len(items))
+	var totals cartTotals
+	for _, item := range items {
+		products[item.Product] = struct{}{}
+		totals.units += item.Quantity
+		totals.value += item.Price.Amount * int64(item.Quantity)
+	}
+	totals.products = len(products)
+	return totals
+}
+
+// checkCartLimits checks the items a cart would hold after a change. A limit
+// only fails when the change grows the cart past it, so carts left over a
+// lowered limit can still shrink.
+func (s *CartService) checkCartLimits(ctx context.Context, before, after []model.CartItem) error {
+	was, will := totalsOf(before), totalsOf(after)
+	if max := s.limits.MaxDistinctProducts; max > 0 && will.products > max && will.products > was.products {
+		return &LimitError{Limit: LimitDistinctProducts, Max: strconv.Itoa(max), Actual: strconv.Itoa(will.products)}
+	}
+	if max := s.limits.MaxUnits; max > 0 && will.units > max && will.units > was.units {
+		return &LimitError{Limit: LimitUnits, Max: strconv.Itoa(max), Actual: strconv.Itoa(will.units)}
+	}
+	if s.limits.MaxCartValue.IsZero() || will.value <= was.value {
+		return nil
+	}
+	currency := after[0].Price.Currency
+	max, err := s.limitIn(ctx, s.limits.MaxCartValue, currency)
+	if err != nil {
+		return err
+	}
+	if will.value > max.Amount {
+		return &LimitError{Limit: LimitCartValue, Max: max.String(), Actual: money.New(will.value, currency).String()}
+	}
+	return nil
+}
+
+// withLine returns items with line replacing the item for the same product,
+// or appended when the cart does not hold that product yet.
+func withLine(items []model.CartItem, line model.CartItem) []model.CartItem {
+	result := make([]model.CartItem, 0, len(items)+1)
+	replaced := false
+	for _, item := range items {
+		if item.Product == line.Product {
+			item = line
+			replaced = true
+		}
+		result = append(result, item)
+	}
+	if !replaced {
+		result = append(result, line)
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateItemLimits(t *testing.T) {
+	limits := Limits{
+		MaxDistinctProducts: 3,
+		MaxUnits:            10,
+		MaxCartValue:        usd("500.00"),
+		MaxItemPrice:        usd("200.00"),
+		MaxNameLength:       12,
+		NamePattern:         `^[A-Za-z ]+$`,
+	}
+	cart := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Apple", Price: usd("100.00"), Quantity: 3},
+		{Id: 2, CartId: 1, Product: "Pear", Price: usd("10.00"), Quantity: 5},
+	}}
+
+	tests := []struct {
+		name     string
+		item     model.CartItem
+		expected *LimitError
+		is       error
+	}{
+		{
+			name:     "Within Limits",
+			item:     model.CartItem{CartId: 1, Product: "Kiwi", Price: usd("1.00"), Quantity: 1},
+			expected: nil,
+		},
+		{
+			name:     "Units",
+			item:     model.CartItem{CartId: 1, Product: "Pear", Price: usd("10.00"), Quantity: 3},
+			expected: &LimitError{Limit: LimitUnits, Max: "10", Actual: "11"},
+			is:       ErrReachCartLimit,
+		},
+		{
+			name:     "Cart Value",
+			item:     model.CartItem{CartId: 1, Product: "Melon", Price: usd("160.00"), Quantity: 1},
+			expected: &LimitError{Limit: LimitCartValue, Max: "500.00", Actual: "510.00"},
+			is:       ErrReachCartLimit,
+		},
+		{
+			name:     "Item Price",
+			item:     model.CartItem{CartId: 1, Product: "Truffle", Price: usd("250.00"), Quantity: 1},
+			expected: &LimitError{Limit: LimitItemPrice, Max: "200.00", Actual: "250.00"},
+			is:       ErrInvalidPrice,
+		},
+		{
+			name:     "Name Length",
+			item:     model.CartItem{CartId: 1, Product: "Golden Delicious", Price: usd("1.00"), Quantity: 1},
+			expected: &LimitError{Limit: LimitNameLength, Max: "12", Actual: "16"},
+			is:       ErrInvalidProduct,
+		},
+		{
+			name:     "Name Charset",
+			item:     model.CartItem{CartId: 1, Product: "Kiwi<script>", Price: usd("1.00"), Quantity: 1},
+			expected: &LimitError{Limit: LimitNameCharset, Pattern: `^[A-Za-z ]+$`},
+			is:       ErrInvalidProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCartRepo)
+			mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+			mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+			mockRepo.On("CreateItem", mock.Anything, mock.Anything).Return(&model.CartItem{Id: 3}, nil)
+
+			service := NewCartService(mockRepo, WithLimits(limits))
+			_, err := service.CreateItem(userCtx(), tt.item)
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var limitErr *LimitError
+			require.ErrorAs(t, err, &limitErr)
+			assert.Equal(t, tt.expected, limitErr)
+			assert.ErrorIs(t, err, tt.is)
+			mockRepo.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateItemDistinctProductLimit(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+	mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Apple", Price: usd("1.00"), Quantity: 1},
+		{Id: 2, CartId: 1, Product: "Pear", Price: usd("1.00"), Quantity: 1},
+	}}, nil)
+
+	service := NewCartService(mockRepo, WithLimits(Limits{MaxDistinctProducts: 2}))
+	_, err := service.CreateItem(userCtx(), model.CartItem{CartId: 1, Product: "Kiwi", Price: usd("1.00"), Quantity: 1})
+
+	assert.Equal(t, &LimitError{Limit: LimitDistinctProducts, Max: "2", Actual: "3"}, err)
+	assert.EqualError(t, err, "cart limit reached: max 2 distinct products")
+}
+
+func TestUpdateItemLimits(t *testing.T) {
+	cart := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Apple", Price: usd("1.00"), Quantity: 8},
+		{Id: 2, CartId: 1, Product: "Pear", Price: usd("1.00"), Quantity: 4},
+	}}
+
+	t.Run("Growing Past Limit", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+
+		service := NewCartService(mockRepo, WithLimits(Limits{MaxUnits: 10}))
+		_, err := service.UpdateItem(userCtx(), model.CartItem{Id: 2, CartId: 1, Quantity: 5})
+
+		assert.ErrorIs(t, err, ErrReachCartLimit)
+		mockRepo.AssertNotCalled(t, "UpdateItemQuantity", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Shrinking Over Limit", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		item := model.CartItem{Id: 1, CartId: 1, Quantity: 7}
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRepo.On("UpdateItemQuantity", mock.Anything, item).Return(&model.CartItem{Id: 1, CartId: 1, Quantity: 7}, nil)
+
+		service := NewCartService(mockRepo, WithLimits(Limits{MaxUnits: 10}))
+		_, err := service.UpdateItem(userCtx(), item)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestCartValueLimitInCartCurrency(t *testing.T) {
+	yen := func(amount string) money.Money { return money.MustParse(amount, "JPY") }
+	cart := &model.Cart{ID: 1, UserID: testUser, Currency: "JPY", Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Tea", Price: yen("50000"), Quantity: 1},
+	}}
+	item := model.CartItem{CartId: 1, Product: "Cups", Price: yen("30000"), Quantity: 1}
+	limits := Limits{MaxCartValue: usd("500.00")}
+
+	t.Run("Converted", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRates := new(MockExchangeRateRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRates.On("GetExchangeRate", mock.Anything, "USD", "JPY").
+			Return(&model.ExchangeRate{Base: "USD", Quote: "JPY", Rate: 15_000_000_000}, nil)
+
+		service := NewCartService(mockRepo, WithLimits(limits), WithExchangeRates(mockRates))
+		_, err := service.CreateItem(userCtx(), item)
+
+		assert.Equal(t, &LimitError{Limit: LimitCartValue, Max: "75000", Actual: "80000"}, err)
+	})
+
+	t.Run("No Exchange Rate", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+
+		service := NewCartService(mockRepo, WithLimits(limits))
+		_, err := service.CreateItem(userCtx(), item)
+
+		assert.ErrorIs(t, err, ErrUnknownExchangeRate)
+		mockRepo.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLimitsValidate(t *testing.T) {
+	assert.NoError(t, DefaultLimits().Validate())
+	assert.Error(t, Limits{MaxUnits: -1}.Validate())
+	assert.Error(t, Limits{NamePattern: "["}.Validate())
+}
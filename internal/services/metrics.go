@@ -0,0 +1,37 @@
+package services
+
+import "errors"
+
+const (
+	DiscountKindRule   = "rule"
+	DiscountKindCoupon = "coupon"
+)
+
+// Metrics receives business events. Without WithMetrics they are discarded.
+type Metrics interface {
+	CartCreated()
+	ItemAdded()
+	LimitRejected(limit string)
+	DiscountApplied(kind string)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) CartCreated()           {}
+func (nopMetrics) ItemAdded()             {}
+func (nopMetrics) LimitRejected(string)   {}
+func (nopMetrics) DiscountApplied(string) {}
+
+func WithMetrics(m Metrics) Option {
+	return func(s *CartService) {
+		s.metrics = m
+	}
+}
+
+// recordLimit counts err if a cart limit rejected the change.
+func (s *CartService) recordLimit(err error) {
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		s.metrics.LimitRejected(limitErr.Limit)
+	}
+}
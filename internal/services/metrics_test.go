@@ -0,0 +1,77 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/pricing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type recordingMetrics struct {
+	carts     int
+	items     int
+	limits    []string
+	discounts []string
+}
+
+func (m *recordingMetrics) CartCreated()                { m.carts++ }
+func (m *recordingMetrics) ItemAdded()                  { m.items++ }
+func (m *recordingMetrics) LimitRejected(limit string)  { m.limits = append(m.limits, limit) }
+func (m *recordingMetrics) DiscountApplied(kind string) { m.discounts = append(m.discounts, kind) }
+
+func TestMetrics(t *testing.T) {
+	t.Run("Cart Created", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("CreateCart", mock.Anything, mock.Anything).Return(&model.Cart{ID: 1}, nil)
+
+		_, err := NewCartService(mockRepo, WithMetrics(metrics)).CreateCart(userCtx())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, metrics.carts)
+	})
+
+	t.Run("Item Added And Limit Rejected", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser}, nil)
+		mockRepo.On("CreateItem", mock.Anything, mock.Anything).Return(&model.CartItem{Id: 1}, nil)
+		service := NewCartService(mockRepo, WithMetrics(metrics), WithLimits(Limits{MaxDistinctProducts: 5, MaxUnits: 3}))
+
+		_, err := service.CreateItem(userCtx(), model.CartItem{CartId: 1, Product: "Apple", Price: usd("1.00"), Quantity: 2})
+		assert.NoError(t, err)
+		_, err = service.CreateItem(userCtx(), model.CartItem{CartId: 1, Product: "Apple", Price: usd("1.00"), Quantity: 4})
+		assert.ErrorIs(t, err, ErrReachCartLimit)
+
+		assert.Equal(t, 1, metrics.items)
+		assert.Equal(t, []string{LimitUnits}, metrics.limits)
+	})
+
+	t.Run("Discounts Applied At Checkout", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		items := []model.CartItem{{Id: 1, CartId: 1, Product: "Socks", Price: usd("10.00"), Quantity: 3}}
+		mockRepo := new(MockCartRepo)
+		mockRules := new(MockDiscountRepo)
+		mockOrders := new(MockOrderRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: items}, nil)
+		mockRepo.On("UpdateCartStatus", mock.Anything, 1, mock.Anything, mock.Anything).Return(nil)
+		mockRules.On("ListActiveRules", mock.Anything).Return([]model.DiscountRule{
+			{Name: "five_off", Kind: "fixed_amount", Priority: 1, Params: []byte(`{"amount": "5.00"}`)},
+		}, nil)
+		mockOrders.On("CreateOrder", mock.Anything, mock.Anything).Return(&model.Order{ID: 7, CartID: 1}, nil)
+		service := NewCartService(mockRepo,
+			WithMetrics(metrics),
+			WithOrders(mockOrders),
+			WithDiscountRules(mockRules, pricing.StrategyStack),
+		)
+
+		_, err := service.Checkout(userCtx(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{DiscountKindRule}, metrics.discounts)
+	})
+}
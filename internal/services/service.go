@@ -1,109 +1,807 @@
 package services
 
 import (
+	"cart-api/internal/auth"
 	"cart-api/internal/model"
+	"cart-api/internal/pricing"
+	"cart-api/internal/shipping"
+	"cart-api/internal/tracing"
+	"cart-api/pkg/money"
 	"context"
+	"errors"
 	"fmt"
-	"math"
+	"go.opentelemetry.io/otel/attribute"
+	"regexp"
 	"strings"
+	"time"
 )
 
 type CartRepository interface {
+	WithTx(context.Context, func(context.Context) error) error
+	LockCart(context.Context, int) (*model.Cart, error)
 	GetCart(context.Context, int) (*model.Cart, error)
-	CreateCart(context.Context) (*model.Cart, error)
-	CreateItem(context.Context, model.CartItem) (int, error)
+	CreateCart(context.Context, model.Cart) (*model.Cart, error)
+	CreateItem(context.Context, model.CartItem) (*model.CartItem, error)
+	UpdateItemQuantity(context.Context, model.CartItem) (*model.CartItem, error)
+	ReplaceItem(context.Context, model.CartItem) (*model.CartItem, error)
 	DeleteItem(context.Context, model.CartItem) error
-	CartExists(context.Context, int) (bool, error)
+	DeleteCart(context.Context, int) error
+	UpdateCartStatus(context.Context, int, model.CartStatus, model.CartStatus) error
+	SetShippingMethod(context.Context, int, string) error
+	ClaimCart(context.Context, int, string, string) (bool, error)
 	ItemExists(context.Context, int) (bool, error)
 }
 
+type DiscountRuleRepository interface {
+	ListActiveRules(context.Context) ([]model.DiscountRule, error)
+}
+
+type CouponRepository interface {
+	GetCoupon(context.Context, string) (*model.Coupon, error)
+	ListCartCoupons(context.Context, int) ([]model.Coupon, error)
+	AttachCoupon(context.Context, int, string) error
+	DetachCoupon(context.Context, int, string) error
+	RedeemCoupon(context.Context, string) error
+}
+
+type OrderRepository interface {
+	CreateOrder(context.Context, model.Order) (*model.Order, error)
+}
+
 type CartService struct {
-	CartRepo CartRepository
+	CartRepo       CartRepository
+	discountRules  DiscountRuleRepository
+	coupons        CouponRepository
+	orders         OrderRepository
+	catalog        ProductRepository
+	inventory      InventoryRepository
+	taxRates       TaxRateRepository
+	taxMode        pricing.TaxMode
+	taxRegion      string
+	shipping       []shipping.Calculator
+	exchangeRates  ExchangeRateRepository
+	limits         Limits
+	metrics        Metrics
+	namePattern    *regexp.Regexp
+	strategy       pricing.Strategy
+	rounding       money.RoundingMode
+	engine         *pricing.Engine
+	now            func() time.Time
+	reservationTTL time.Duration
 }
 
-func NewCartService(cartRepo CartRepository) *CartService {
-	return &CartService{
-		cartRepo,
+type Option func(*CartService)
+
+func WithRounding(mode money.RoundingMode) Option {
+	return func(s *CartService) {
+		s.rounding = mode
 	}
 }
 
-func (s *CartService) CreateCart(ctx context.Context) (*model.Cart, error) {
-	return s.CartRepo.CreateCart(ctx)
+func WithDiscountRules(repo DiscountRuleRepository, strategy pricing.Strategy) Option {
+	return func(s *CartService) {
+		s.discountRules = repo
+		s.strategy = strategy
+	}
+}
+
+func WithCoupons(repo CouponRepository) Option {
+	return func(s *CartService) {
+		s.coupons = repo
+	}
 }
 
-func (s *CartService) CreateItem(ctx context.Context, item model.CartItem) (int, error) {
-	exists, err := s.CartRepo.CartExists(ctx, item.CartId)
+func WithOrders(repo OrderRepository) Option {
+	return func(s *CartService) {
+		s.orders = repo
+	}
+}
+
+// WithCatalog makes CreateItem resolve items by SKU, taking the name and price
+// from the catalog instead of the request.
+func WithCatalog(repo ProductRepository) Option {
+	return func(s *CartService) {
+		s.catalog = repo
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(s *CartService) {
+		s.now = now
+	}
+}
+
+func NewCartService(cartRepo CartRepository, opts ...Option) *CartService {
+	s := &CartService{
+		CartRepo:       cartRepo,
+		strategy:       pricing.StrategyBest,
+		taxMode:        pricing.TaxExclusive,
+		rounding:       money.RoundHalfUp,
+		limits:         DefaultLimits(),
+		metrics:        nopMetrics{},
+		now:            time.Now,
+		reservationTTL: defaultReservationTTL,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.engine = pricing.NewEngine(s.strategy, s.rounding)
+	return s
+}
+
+func (s *CartService) CreateCart(ctx context.Context) (_ *model.Cart, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.CreateCart")
+	defer func() { tracing.End(span, err) }()
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	cart := model.Cart{SessionID: principal.SessionID}
+	if !principal.IsGuest() {
+		cart.UserID = principal.UserID
+	}
+	created, err := s.CartRepo.CreateCart(ctx, cart)
 	if err != nil {
-		return 0, fmt.Errorf("failed to check cart existence: %w", err)
+		return nil, err
 	}
-	if !exists {
-		return 0, ErrCartNotFound
+	s.metrics.CartCreated()
+	return created, nil
+}
+
+func (s *CartService) CreateItem(ctx context.Context, item model.CartItem) (_ *model.CartItem, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.CreateItem", attribute.Int("cart.id", item.CartId), attribute.String("product.sku", item.SKU))
+	defer func() { tracing.End(span, err) }()
+	if s.catalog != nil {
+		resolved, err := s.resolveProduct(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		item = resolved
 	}
 	if strings.TrimSpace(item.Product) == "" {
-		return 0, ErrInvalidProduct
+		return nil, ErrInvalidProduct
+	}
+	if item.Price.IsNegative() {
+		return nil, ErrInvalidPrice
+	}
+	if item.Price.Currency == "" {
+		item.Price.Currency = money.DefaultCurrency
+	}
+	if !money.ValidCurrency(item.Price.Currency) {
+		return nil, ErrInvalidCurrency
 	}
-	if item.Price < 0 {
-		return 0, ErrInvalidPrice
+	if item.Quantity == 0 {
+		item.Quantity = 1
 	}
-	cart, err := s.GetCart(ctx, item.CartId)
+	if item.Quantity < 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if err := s.checkItemLimits(ctx, item); err != nil {
+		s.recordLimit(err)
+		return nil, err
+	}
+	var created *model.CartItem
+	err = s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOpenCart(ctx, item.CartId); err != nil {
+			return err
+		}
+		cart, err := s.CartRepo.GetCart(ctx, item.CartId)
+		if err != nil {
+			return fmt.Errorf("failed to get cart: %w", err)
+		}
+		if currency := cartCurrency(cart); currency != "" && currency != item.Price.Currency {
+			return fmt.Errorf("%w: cart is in %s, item is in %s", ErrCurrencyMismatch, currency, item.Price.Currency)
+		}
+		line := item
+		for _, existing := range cart.Items {
+			if existing.Product == item.Product {
+				line = existing
+				line.Quantity += item.Quantity
+			}
+		}
+		if err = s.checkCartLimits(ctx, cart.Items, withLine(cart.Items, line)); err != nil {
+			return err
+		}
+		if err = s.reserve(ctx, item.CartId, item.SKU, line.Quantity); err != nil {
+			return err
+		}
+		created, err = s.CartRepo.CreateItem(ctx, item)
+		return err
+	})
+	if err != nil {
+		s.recordLimit(err)
+		return nil, err
+	}
+	s.metrics.ItemAdded()
+	return created, nil
+}
+
+func (s *CartService) resolveProduct(ctx context.Context, item model.CartItem) (model.CartItem, error) {
+	if strings.TrimSpace(item.SKU) == "" {
+		return item, ErrUnknownProduct
+	}
+	product, err := s.catalog.GetProduct(ctx, item.SKU)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get cart: %w", err)
+		return item, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return item, ErrUnknownProduct
+	}
+	if !product.Active {
+		return item, ErrProductInactive
+	}
+	item.SKU = product.SKU
+	item.Product = product.Name
+	item.Price = product.Price
+	item.TaxCategory = product.TaxCategory
+	item.Dimensions = product.Dimensions
+	return item, nil
+}
+
+func (s *CartService) UpdateItem(ctx context.Context, item model.CartItem) (_ *model.CartItem, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.UpdateItem", attribute.Int("cart.id", item.CartId), attribute.Int("cart.item.id", item.Id))
+	defer func() { tracing.End(span, err) }()
+	if item.Quantity <= 0 {
+		return nil, ErrInvalidQuantity
 	}
-	uniqueProducts := make(map[string]struct{})
-	productAlreadyExist := false
-	for _, existingItem := range cart.Items {
-		uniqueProducts[existingItem.Product] = struct{}{}
-		if existingItem.Product == item.Product {
-			productAlreadyExist = true
+	var updated *model.CartItem
+	err = s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOpenCart(ctx, item.CartId); err != nil {
+			return err
 		}
+		if s.limits.MaxUnits > 0 || !s.limits.MaxCartValue.IsZero() {
+			if err := s.checkQuantityLimits(ctx, item); err != nil {
+				return err
+			}
+		}
+		var err error
+		updated, err = s.CartRepo.UpdateItemQuantity(ctx, item)
+		if err != nil {
+			return err
+		}
+		return s.reserve(ctx, updated.CartId, updated.SKU, updated.Quantity)
+	})
+	if err != nil {
+		s.recordLimit(err)
+		return nil, err
 	}
-	if len(uniqueProducts) >= 5 && !productAlreadyExist {
-		return 0, ErrReachCartLimit
+	return updated, nil
+}
+
+// checkQuantityLimits checks the unit and value limits for changing the
+// quantity of item. Unknown items are left for UpdateItemQuantity to report.
+func (s *CartService) checkQuantityLimits(ctx context.Context, item model.CartItem) error {
+	cart, err := s.CartRepo.GetCart(ctx, item.CartId)
+	if err != nil {
+		return fmt.Errorf("failed to get cart: %w", err)
 	}
-	return s.CartRepo.CreateItem(ctx, item)
+	for _, line := range cart.Items {
+		if line.Id == item.Id {
+			line.Quantity = item.Quantity
+			return s.checkCartLimits(ctx, cart.Items, withLine(cart.Items, line))
+		}
+	}
+	return nil
 }
 
-func (s *CartService) DeleteItem(ctx context.Context, item model.CartItem) error {
-	exists, err := s.CartRepo.ItemExists(ctx, item.Id)
+func (s *CartService) DeleteItem(ctx context.Context, item model.CartItem) (err error) {
+	ctx, span := tracing.Start(ctx, "CartService.DeleteItem", attribute.Int("cart.id", item.CartId), attribute.Int("cart.item.id", item.Id))
+	defer func() { tracing.End(span, err) }()
+	return s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOpenCart(ctx, item.CartId); err != nil {
+			return err
+		}
+		exists, err := s.CartRepo.ItemExists(ctx, item.Id)
+		if err != nil {
+			return fmt.Errorf("failed to check item existence: %w", err)
+		}
+		if !exists {
+			return ErrItemNotFound
+		}
+		if s.inventory != nil {
+			cart, err := s.CartRepo.GetCart(ctx, item.CartId)
+			if err != nil {
+				return fmt.Errorf("failed to get cart: %w", err)
+			}
+			for _, line := range cart.Items {
+				if line.Id == item.Id {
+					if err = s.release(ctx, line.CartId, line.SKU); err != nil {
+						return fmt.Errorf("failed to release stock: %w", err)
+					}
+				}
+			}
+		}
+		return s.CartRepo.DeleteItem(ctx, item)
+	})
+}
+
+// MergeCart moves the items of a guest cart into the signed-in user's cart and
+// deletes the guest cart. Products present in both carts keep the summed
+// quantity and the price of the most recently added line; products that do not
+// fit under the distinct product limit are reported as conflicts and dropped.
+func (s *CartService) MergeCart(ctx context.Context, cartID, guestCartID int) (_ *model.MergeResult, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.MergeCart", attribute.Int("cart.id", cartID), attribute.Int("cart.guest_id", guestCartID))
+	defer func() { tracing.End(span, err) }()
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if principal.IsGuest() {
+		return nil, ErrMergeTargetGuest
+	}
+	if cartID == guestCartID {
+		return nil, ErrMergeSameCart
+	}
+	result := &model.MergeResult{}
+	err = s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		target, guest, err := s.lockPair(ctx, cartID, guestCartID)
+		if err != nil {
+			return err
+		}
+		if target.UserID == "" {
+			return ErrMergeTargetGuest
+		}
+		if guest.UserID != "" {
+			return ErrMergeNotGuest
+		}
+		if err = s.authorize(ctx, target); err != nil {
+			return err
+		}
+		if err = s.authorize(ctx, guest); err != nil {
+			return err
+		}
+		if target.Status != model.CartOpen || guest.Status != model.CartOpen {
+			return ErrCartNotOpen
+		}
+		targetCart, err := s.CartRepo.GetCart(ctx, cartID)
+		if err != nil {
+			return fmt.Errorf("failed to get cart: %w", err)
+		}
+		guestCart, err := s.CartRepo.GetCart(ctx, guestCartID)
+		if err != nil {
+			return fmt.Errorf("failed to get guest cart: %w", err)
+		}
+		if s.inventory != nil {
+			if err = s.inventory.ReleaseCart(ctx, guestCartID); err != nil {
+				return fmt.Errorf("failed to release guest cart stock: %w", err)
+			}
+		}
+		existing := make(map[string]model.CartItem, len(targetCart.Items))
+		for _, item := range targetCart.Items {
+			existing[item.Product] = item
+		}
+		currency := cartCurrency(targetCart)
+		if currency == "" {
+			currency = cartCurrency(guestCart)
+		}
+		for _, item := range guestCart.Items {
+			if item.Price.Currency != currency {
+				result.Conflicts = append(result.Conflicts, model.MergeConflict{
+					Product:  item.Product,
+					Quantity: item.Quantity,
+					Reason:   ErrCurrencyMismatch.Error(),
+				})
+				continue
+			}
+			if current, ok := existing[item.Product]; ok {
+				merged := current
+				merged.Quantity += item.Quantity
+				if item.Id > current.Id {
+					merged.Price = item.Price
+				}
+				within, err := s.mergeWithinLimits(ctx, result, existing, merged, item)
+				if err != nil {
+					return err
+				}
+				if !within {
+					continue
+				}
+				reserved, err := s.mergeReserve(ctx, result, cartID, merged, item)
+				if err != nil {
+					return err
+				}
+				if !reserved {
+					continue
+				}
+				if _, err = s.CartRepo.ReplaceItem(ctx, merged); err != nil {
+					return fmt.Errorf("failed to merge item %q: %w", item.Product, err)
+				}
+				existing[item.Product] = merged
+				continue
+			}
+			moved := item
+			moved.Id = 0
+			moved.CartId = cartID
+			within, err := s.mergeWithinLimits(ctx, result, existing, moved, item)
+			if err != nil {
+				return err
+			}
+			if !within {
+				continue
+			}
+			reserved, err := s.mergeReserve(ctx, result, cartID, moved, item)
+			if err != nil {
+				return err
+			}
+			if !reserved {
+				continue
+			}
+			created, err := s.CartRepo.CreateItem(ctx, moved)
+			if err != nil {
+				return fmt.Errorf("failed to move item %q: %w", item.Product, err)
+			}
+			existing[item.Product] = *created
+		}
+		if err = s.CartRepo.DeleteCart(ctx, guestCartID); err != nil {
+			return fmt.Errorf("failed to delete guest cart: %w", err)
+		}
+		result.Cart, err = s.CartRepo.GetCart(ctx, cartID)
+		if err != nil {
+			return fmt.Errorf("failed to get merged cart: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to check item existence: %w", err)
+		return nil, err
 	}
-	if !exists {
-		return ErrItemNotFound
+	return result, nil
+}
+
+// mergeWithinLimits reports whether the merged cart may hold line. When it
+// may not, the guest item is recorded as a conflict.
+func (s *CartService) mergeWithinLimits(ctx context.Context, result *model.MergeResult, existing map[string]model.CartItem, line, guestItem model.CartItem) (bool, error) {
+	lines := make([]model.CartItem, 0, len(existing))
+	for _, item := range existing {
+		lines = append(lines, item)
+	}
+	err := s.checkCartLimits(ctx, lines, withLine(lines, line))
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		result.Conflicts = append(result.Conflicts, model.MergeConflict{
+			Product:  guestItem.Product,
+			Quantity: guestItem.Quantity,
+			Reason:   limitErr.Error(),
+		})
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// mergeReserve reserves stock for a line of the merged cart. When there is not
+// enough stock the guest item is recorded as a conflict and false is returned.
+func (s *CartService) mergeReserve(ctx context.Context, result *model.MergeResult, cartID int, line, guestItem model.CartItem) (bool, error) {
+	err := s.reserve(ctx, cartID, line.SKU, line.Quantity)
+	var stockErr *InsufficientStockError
+	if errors.As(err, &stockErr) {
+		result.Conflicts = append(result.Conflicts, model.MergeConflict{
+			Product:  guestItem.Product,
+			Quantity: guestItem.Quantity,
+			Reason:   stockErr.Error(),
+		})
+		return false, nil
 	}
-	return s.CartRepo.DeleteItem(ctx, item)
+	return err == nil, err
 }
 
-func (s *CartService) GetCart(ctx context.Context, id int) (*model.Cart, error) {
+func (s *CartService) GetCart(ctx context.Context, id int) (_ *model.Cart, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.GetCart", attribute.Int("cart.id", id))
+	defer func() { tracing.End(span, err) }()
 	cart, err := s.CartRepo.GetCart(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cart: %w", err)
 	}
+	if err = s.authorize(ctx, cart); err != nil {
+		return nil, err
+	}
 	return cart, nil
 }
 
-func (s *CartService) GetPrice(ctx context.Context, id int) (*model.Price, error) {
+func (s *CartService) GetPrice(ctx context.Context, id int) (_ *model.Price, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.GetPrice", attribute.Int("cart.id", id))
+	defer func() { tracing.End(span, err) }()
 	carts, err := s.CartRepo.GetCart(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("getting cart for price failed: %w", err)
 	}
-	price := &model.Price{}
-	var totalPrice float64
-	var totalNumbers int
-
-	for _, currPrice := range carts.Items {
-		totalNumbers++
-		totalPrice += currPrice.Price
+	if err = s.authorize(ctx, carts); err != nil {
+		return nil, err
+	}
+	price, err := s.price(ctx, carts)
+	if err != nil {
+		return nil, err
 	}
+	if currency, ok := DisplayCurrency(ctx); ok {
+		return s.convertPrice(ctx, price, currency)
+	}
+	return price, nil
+}
 
-	price.TotalPrice = totalPrice
-	if totalNumbers > 3 {
-		price.DiscountPercent = 5
+func (s *CartService) price(ctx context.Context, carts *model.Cart) (*model.Price, error) {
+	in, price, err := s.discountedPrice(ctx, carts)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.applyTax(ctx, in, price); err != nil {
+		return nil, err
 	}
-	if totalPrice > 5000 {
-		price.DiscountPercent = 10
+	if err = s.applyShipping(carts, in, price); err != nil {
+		return nil, err
 	}
-	price.FinalPrice = math.Trunc((totalPrice-totalPrice*(float64(price.DiscountPercent)/100))*100) / 100
-	price.CartId = carts.ID
 	return price, nil
 }
+
+// discountedPrice applies discount rules and coupons; FinalPrice is what the
+// goods cost before tax and shipping.
+func (s *CartService) discountedPrice(ctx context.Context, carts *model.Cart) (pricing.Input, *model.Price, error) {
+	rules, err := s.activeRules(ctx)
+	if err != nil {
+		return pricing.Input{}, nil, err
+	}
+	currency := cartCurrency(carts)
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	in := pricing.NewInput(carts.Items, currency)
+	if in.Convert, err = s.configuredIn(ctx, currency); err != nil {
+		return pricing.Input{}, nil, err
+	}
+
+	price := &model.Price{
+		CartId:         carts.ID,
+		TotalPrice:     in.Subtotal,
+		DiscountAmount: money.New(0, currency),
+		Discounts:      s.engine.Apply(rules, in),
+	}
+	for _, discount := range price.Discounts {
+		price.DiscountAmount = price.DiscountAmount.Add(discount.Amount)
+	}
+	if in.Subtotal.Amount > 0 {
+		price.DiscountPercent = int(price.DiscountAmount.Amount * 100 / in.Subtotal.Amount)
+	}
+	remaining := in.Subtotal.Sub(price.DiscountAmount)
+
+	coupons, err := s.cartCoupons(ctx, carts.ID)
+	if err != nil {
+		return pricing.Input{}, nil, err
+	}
+	for _, coupon := range coupons {
+		if s.validateCoupon(coupon, in.Subtotal) != nil {
+			continue
+		}
+		amount := s.couponAmount(coupon, remaining)
+		price.Coupons = append(price.Coupons, model.AppliedCoupon{Code: coupon.Code, Amount: amount})
+		remaining = remaining.Sub(amount)
+	}
+	price.FinalPrice = remaining
+	return in, price, nil
+}
+
+func (s *CartService) ApplyCoupon(ctx context.Context, cartID int, code string) (_ *model.Coupon, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.ApplyCoupon", attribute.Int("cart.id", cartID))
+	defer func() { tracing.End(span, err) }()
+	code = normalizeCouponCode(code)
+	if code == "" || s.coupons == nil {
+		return nil, ErrCouponNotFound
+	}
+	var coupon *model.Coupon
+	err = s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		coupon, err = s.applyCoupon(ctx, cartID, code)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+func (s *CartService) applyCoupon(ctx context.Context, cartID int, code string) (*model.Coupon, error) {
+	if _, err := s.lockOpenCart(ctx, cartID); err != nil {
+		return nil, err
+	}
+	cart, err := s.CartRepo.GetCart(ctx, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	coupon, err := s.coupons.GetCoupon(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coupon: %w", err)
+	}
+	currency := cartCurrency(cart)
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	if err = s.validateCoupon(*coupon, pricing.NewInput(cart.Items, currency).Subtotal); err != nil {
+		return nil, err
+	}
+	if coupon.UsageLimit != nil && coupon.UsedCount >= *coupon.UsageLimit {
+		return nil, ErrCouponUsageLimit
+	}
+	applied, err := s.coupons.ListCartCoupons(ctx, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cart coupons: %w", err)
+	}
+	for _, existing := range applied {
+		if existing.Code == coupon.Code {
+			return nil, ErrCouponAlreadyApplied
+		}
+		if !existing.Stackable || !coupon.Stackable {
+			return nil, ErrCouponNotStackable
+		}
+	}
+	if err = s.coupons.AttachCoupon(ctx, cartID, coupon.Code); err != nil {
+		return nil, fmt.Errorf("failed to apply coupon: %w", err)
+	}
+	return coupon, nil
+}
+
+func (s *CartService) RemoveCoupon(ctx context.Context, cartID int, code string) (err error) {
+	ctx, span := tracing.Start(ctx, "CartService.RemoveCoupon", attribute.Int("cart.id", cartID))
+	defer func() { tracing.End(span, err) }()
+	code = normalizeCouponCode(code)
+	if code == "" || s.coupons == nil {
+		return ErrCouponNotFound
+	}
+	return s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOpenCart(ctx, cartID); err != nil {
+			return err
+		}
+		if err := s.coupons.DetachCoupon(ctx, cartID, code); err != nil {
+			return fmt.Errorf("failed to remove coupon: %w", err)
+		}
+		return nil
+	})
+}
+
+// lockPair locks both carts in id order so concurrent merges cannot deadlock.
+func (s *CartService) lockPair(ctx context.Context, cartID, otherID int) (*model.Cart, *model.Cart, error) {
+	locked := make(map[int]*model.Cart, 2)
+	for _, id := range []int{min(cartID, otherID), max(cartID, otherID)} {
+		cart, err := s.CartRepo.LockCart(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to lock cart: %w", err)
+		}
+		if cart == nil {
+			return nil, nil, ErrCartNotFound
+		}
+		locked[id] = cart
+	}
+	return locked[cartID], locked[otherID], nil
+}
+
+func (s *CartService) lockOpenCart(ctx context.Context, cartID int) (*model.Cart, error) {
+	cart, err := s.lockCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+	if cart.Status != model.CartOpen {
+		return nil, ErrCartNotOpen
+	}
+	if err = checkVersion(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// lockCart locks the caller's cart whatever its status. Callers check the
+// status and then the expected version with checkVersion.
+func (s *CartService) lockCart(ctx context.Context, cartID int) (*model.Cart, error) {
+	cart, err := s.CartRepo.LockCart(ctx, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock cart: %w", err)
+	}
+	if cart == nil {
+		return nil, ErrCartNotFound
+	}
+	if err = s.authorize(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+func checkVersion(ctx context.Context, cart *model.Cart) error {
+	if version, ok := ExpectedVersion(ctx); ok && version != cart.Version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+func (s *CartService) authorize(ctx context.Context, cart *model.Cart) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if cart.UserID == "" && cart.SessionID == "" {
+		return s.claim(ctx, cart, principal)
+	}
+	if cart.UserID != "" {
+		if principal.UserID != cart.UserID {
+			return ErrForbidden
+		}
+		return nil
+	}
+	if cart.SessionID == "" || principal.SessionID != cart.SessionID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// claim hands a cart without owner, one created before carts had owners, to
+// the first principal that accesses it. Losing the race to another principal
+// is the same as accessing a cart owned by someone else.
+func (s *CartService) claim(ctx context.Context, cart *model.Cart, principal auth.Principal) error {
+	claimed, err := s.CartRepo.ClaimCart(ctx, cart.ID, principal.UserID, principal.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to claim cart: %w", err)
+	}
+	if !claimed {
+		return ErrForbidden
+	}
+	cart.UserID, cart.SessionID = principal.UserID, principal.SessionID
+	return nil
+}
+
+func (s *CartService) cartCoupons(ctx context.Context, cartID int) ([]model.Coupon, error) {
+	if s.coupons == nil {
+		return nil, nil
+	}
+	coupons, err := s.coupons.ListCartCoupons(ctx, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cart coupons: %w", err)
+	}
+	return coupons, nil
+}
+
+func (s *CartService) validateCoupon(coupon model.Coupon, subtotal money.Money) error {
+	now := s.now()
+	if !coupon.Active || (coupon.StartsAt != nil && now.Before(*coupon.StartsAt)) {
+		return ErrCouponNotActive
+	}
+	if coupon.ExpiresAt != nil && !now.Before(*coupon.ExpiresAt) {
+		return ErrCouponExpired
+	}
+	if !couponMatchesCurrency(coupon, subtotal.Currency) {
+		return fmt.Errorf("%w: coupon is in %s, cart is in %s", ErrCouponCurrency, coupon.Amount.Currency, subtotal.Currency)
+	}
+	if subtotal.Amount < coupon.MinTotal.Amount {
+		return ErrCouponMinTotal
+	}
+	return nil
+}
+
+// couponMatchesCurrency reports whether the fixed amounts of coupon can be
+// used in currency. A percentage coupon without a minimum total fits any cart.
+func couponMatchesCurrency(coupon model.Coupon, currency string) bool {
+	for _, amount := range []money.Money{coupon.Amount, coupon.MinTotal} {
+		if !amount.IsZero() && amount.Currency != currency {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *CartService) couponAmount(coupon model.Coupon, remaining money.Money) money.Money {
+	if coupon.Percent > 0 {
+		return remaining.Percent(int64(coupon.Percent), s.rounding)
+	}
+	return money.New(min(coupon.Amount.Amount, remaining.Amount), remaining.Currency)
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (s *CartService) activeRules(ctx context.Context) ([]pricing.DiscountRule, error) {
+	if s.discountRules == nil {
+		return pricing.DefaultRules(), nil
+	}
+	defs, err := s.discountRules.ListActiveRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load discount rules: %w", err)
+	}
+	rules, err := pricing.BuildAll(defs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build discount rules: %w", err)
+	}
+	return rules, nil
+}
@@ -1,63 +1,127 @@
 package services
 
 import (
+	"cart-api/internal/auth"
 	"cart-api/internal/model"
+	"cart-api/internal/pricing"
+	"cart-api/pkg/money"
+	"context"
 	"errors"
+	"fmt"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+const testUser = "user-1"
+
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.DefaultCurrency)
+}
+
+func userCtx() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: testUser})
+}
+
 type MockCartRepo struct {
 	mock.Mock
 }
 
-func (m *MockCartRepo) GetCart(id int) (*model.Cart, error) {
-	args := m.Called(id)
+func (m *MockCartRepo) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *MockCartRepo) LockCart(ctx context.Context, id int) (*model.Cart, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Cart), args.Error(1)
+}
+
+func (m *MockCartRepo) UpdateCartStatus(ctx context.Context, id int, from, to model.CartStatus) error {
+	args := m.Called(ctx, id, from, to)
+	return args.Error(0)
+}
+
+func (m *MockCartRepo) SetShippingMethod(ctx context.Context, id int, method string) error {
+	args := m.Called(ctx, id, method)
+	return args.Error(0)
+}
+
+func (m *MockCartRepo) ClaimCart(ctx context.Context, id int, userID, sessionID string) (bool, error) {
+	args := m.Called(ctx, id, userID, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCartRepo) GetCart(ctx context.Context, id int) (*model.Cart, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Cart), args.Error(1)
 }
 
-func (m *MockCartRepo) CreateCart() (*model.Cart, error) {
-	args := m.Called()
+func (m *MockCartRepo) CreateCart(ctx context.Context, cart model.Cart) (*model.Cart, error) {
+	args := m.Called(ctx, cart)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Cart), args.Error(1)
 }
 
-func (m *MockCartRepo) CreateItem(item model.CartItem) (int, error) {
-	args := m.Called(item)
-	return args.Int(0), args.Error(1)
+func (m *MockCartRepo) CreateItem(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CartItem), args.Error(1)
+}
+
+func (m *MockCartRepo) UpdateItemQuantity(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CartItem), args.Error(1)
+}
+
+func (m *MockCartRepo) ReplaceItem(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CartItem), args.Error(1)
 }
 
-func (m *MockCartRepo) DeleteItem(item model.CartItem) error {
-	args := m.Called(item)
+func (m *MockCartRepo) DeleteCart(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockCartRepo) CartExists(id int) (bool, error) {
-	args := m.Called(id)
-	return args.Bool(0), args.Error(1)
+func (m *MockCartRepo) DeleteItem(ctx context.Context, item model.CartItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
 }
 
-func (m *MockCartRepo) ItemExists(id int) (bool, error) {
-	args := m.Called(id)
+func (m *MockCartRepo) ItemExists(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
 	return args.Bool(0), args.Error(1)
 }
 
 func TestCreateCart(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockCartRepo)
-		expectedCart := &model.Cart{ID: 1, Items: []model.CartItem{}}
+		expectedCart := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{}}
 
-		mockRepo.On("CreateCart").Return(expectedCart, nil)
+		mockRepo.On("CreateCart", mock.Anything, model.Cart{UserID: testUser}).Return(expectedCart, nil)
 
 		service := NewCartService(mockRepo)
-		result, err := service.CreateCart()
+		result, err := service.CreateCart(userCtx())
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedCart, result)
@@ -66,10 +130,10 @@ func TestCreateCart(t *testing.T) {
 
 	t.Run("Repo Error", func(t *testing.T) {
 		mockRepo := new(MockCartRepo)
-		mockRepo.On("CreateCart").Return(nil, errors.New("db fail"))
+		mockRepo.On("CreateCart", mock.Anything, model.Cart{UserID: testUser}).Return(nil, errors.New("db fail"))
 
 		service := NewCartService(mockRepo)
-		result, err := service.CreateCart()
+		result, err := service.CreateCart(userCtx())
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -80,17 +144,66 @@ func TestCreateCart(t *testing.T) {
 func TestCreateItem(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockCartRepo)
-		item := model.CartItem{CartId: 1, Product: "Apple", Price: 100}
-		expectedID := 123
+		item := model.CartItem{CartId: 1, Product: "Apple", Price: usd("100"), Quantity: 2}
+		expectedItem := &model.CartItem{Id: 123, CartId: 1, Product: "Apple", Price: usd("100"), Quantity: 2}
+
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(&model.Cart{ID: item.CartId, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, item.CartId).Return(&model.Cart{ID: 1, UserID: testUser}, nil)
+		mockRepo.On("CreateItem", mock.Anything, item).Return(expectedItem, nil)
+
+		service := NewCartService(mockRepo)
+		created, err := service.CreateItem(userCtx(), item)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedItem, created)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Default Quantity", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		item := model.CartItem{CartId: 1, Product: "Apple", Price: usd("100")}
 
-		mockRepo.On("CartExists", item.CartId).Return(true, nil)
-		mockRepo.On("CreateItem", item).Return(expectedID, nil)
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(&model.Cart{ID: item.CartId, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, item.CartId).Return(&model.Cart{ID: 1, UserID: testUser}, nil)
+		mockRepo.On("CreateItem", mock.Anything, mock.MatchedBy(func(i model.CartItem) bool {
+			return i.Quantity == 1
+		})).Return(&model.CartItem{Id: 1, Quantity: 1}, nil)
 
 		service := NewCartService(mockRepo)
-		id, err := service.CreateItem(item)
+		_, err := service.CreateItem(userCtx(), item)
 
 		assert.NoError(t, err)
-		assert.Equal(t, expectedID, id)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Quantity", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		item := model.CartItem{CartId: 1, Product: "Apple", Price: usd("100"), Quantity: -1}
+
+		service := NewCartService(mockRepo)
+		created, err := service.CreateItem(userCtx(), item)
+
+		assert.ErrorIs(t, err, ErrInvalidQuantity)
+		assert.Nil(t, created)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cart Limit Reached", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		item := model.CartItem{CartId: 1, Product: "Kiwi", Price: usd("100"), Quantity: 1}
+		cart := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+			{Product: "A", Quantity: 1}, {Product: "B", Quantity: 1}, {Product: "C", Quantity: 1},
+			{Product: "D", Quantity: 1}, {Product: "E", Quantity: 3},
+		}}
+
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(&model.Cart{ID: item.CartId, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, item.CartId).Return(cart, nil)
+
+		service := NewCartService(mockRepo)
+		created, err := service.CreateItem(userCtx(), item)
+
+		assert.ErrorIs(t, err, ErrReachCartLimit)
+		assert.Nil(t, created)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -98,29 +211,73 @@ func TestCreateItem(t *testing.T) {
 		mockRepo := new(MockCartRepo)
 		item := model.CartItem{CartId: 999, Product: "Apple"}
 
-		mockRepo.On("CartExists", item.CartId).Return(false, nil)
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(nil, nil)
 
 		service := NewCartService(mockRepo)
-		id, err := service.CreateItem(item)
+		created, err := service.CreateItem(userCtx(), item)
 
 		assert.Error(t, err)
 		assert.Equal(t, ErrCartNotFound, err)
-		assert.Zero(t, id)
+		assert.Nil(t, created)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Repo Error on Create", func(t *testing.T) {
 		mockRepo := new(MockCartRepo)
-		item := model.CartItem{CartId: 1, Product: "Apple"}
+		item := model.CartItem{CartId: 1, Product: "Apple", Price: usd("0"), Quantity: 1}
 
-		mockRepo.On("CartExists", item.CartId).Return(true, nil)
-		mockRepo.On("CreateItem", item).Return(0, errors.New("insert failed"))
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(&model.Cart{ID: item.CartId, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("GetCart", mock.Anything, item.CartId).Return(&model.Cart{ID: 1, UserID: testUser}, nil)
+		mockRepo.On("CreateItem", mock.Anything, item).Return(nil, errors.New("insert failed"))
 
 		service := NewCartService(mockRepo)
-		id, err := service.CreateItem(item)
+		created, err := service.CreateItem(userCtx(), item)
 
 		assert.Error(t, err)
-		assert.Zero(t, id)
+		assert.Nil(t, created)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdateItem(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		item := model.CartItem{Id: 10, CartId: 1, Quantity: 4}
+		expectedItem := &model.CartItem{Id: 10, CartId: 1, Product: "Apple", Price: usd("100"), Quantity: 4}
+
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(&model.Cart{ID: item.CartId, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("UpdateItemQuantity", mock.Anything, item).Return(expectedItem, nil)
+
+		service := NewCartService(mockRepo)
+		updated, err := service.UpdateItem(userCtx(), item)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedItem, updated)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Quantity", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+
+		service := NewCartService(mockRepo)
+		updated, err := service.UpdateItem(userCtx(), model.CartItem{Id: 10, CartId: 1})
+
+		assert.ErrorIs(t, err, ErrInvalidQuantity)
+		assert.Nil(t, updated)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cart Not Found", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		item := model.CartItem{Id: 10, CartId: 999, Quantity: 2}
+
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(nil, nil)
+
+		service := NewCartService(mockRepo)
+		updated, err := service.UpdateItem(userCtx(), item)
+
+		assert.ErrorIs(t, err, ErrCartNotFound)
+		assert.Nil(t, updated)
 		mockRepo.AssertExpectations(t)
 	})
 }
@@ -130,11 +287,12 @@ func TestDeleteItem(t *testing.T) {
 		mockRepo := new(MockCartRepo)
 		item := model.CartItem{Id: 10, CartId: 5}
 
-		mockRepo.On("ItemExists", item.Id).Return(true, nil)
-		mockRepo.On("DeleteItem", item).Return(nil)
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(&model.Cart{ID: item.CartId, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("ItemExists", mock.Anything, item.Id).Return(true, nil)
+		mockRepo.On("DeleteItem", mock.Anything, item).Return(nil)
 
 		service := NewCartService(mockRepo)
-		err := service.DeleteItem(item)
+		err := service.DeleteItem(userCtx(), item)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -144,10 +302,11 @@ func TestDeleteItem(t *testing.T) {
 		mockRepo := new(MockCartRepo)
 		item := model.CartItem{Id: 10, CartId: 5}
 
-		mockRepo.On("ItemExists", item.Id).Return(false, nil)
+		mockRepo.On("LockCart", mock.Anything, item.CartId).Return(&model.Cart{ID: item.CartId, UserID: testUser, Status: model.CartOpen}, nil)
+		mockRepo.On("ItemExists", mock.Anything, item.Id).Return(false, nil)
 
 		service := NewCartService(mockRepo)
-		err := service.DeleteItem(item)
+		err := service.DeleteItem(userCtx(), item)
 
 		assert.Error(t, err)
 		assert.Equal(t, ErrItemNotFound, err)
@@ -158,12 +317,12 @@ func TestDeleteItem(t *testing.T) {
 func TestGetCart(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockCartRepo)
-		expectedCart := &model.Cart{ID: 55}
+		expectedCart := &model.Cart{ID: 55, UserID: testUser}
 
-		mockRepo.On("GetCart", 55).Return(expectedCart, nil)
+		mockRepo.On("GetCart", mock.Anything, 55).Return(expectedCart, nil)
 
 		service := NewCartService(mockRepo)
-		cart, err := service.GetCart(55)
+		cart, err := service.GetCart(userCtx(), 55)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedCart, cart)
@@ -172,10 +331,10 @@ func TestGetCart(t *testing.T) {
 
 	t.Run("Repo Error", func(t *testing.T) {
 		mockRepo := new(MockCartRepo)
-		mockRepo.On("GetCart", 55).Return(nil, errors.New("db error"))
+		mockRepo.On("GetCart", mock.Anything, 55).Return(nil, errors.New("db error"))
 
 		service := NewCartService(mockRepo)
-		cart, err := service.GetCart(55)
+		cart, err := service.GetCart(userCtx(), 55)
 
 		assert.Error(t, err)
 		assert.Nil(t, cart)
@@ -183,13 +342,248 @@ func TestGetCart(t *testing.T) {
 	})
 }
 
+func TestCartOwnership(t *testing.T) {
+	userCart := &model.Cart{ID: 1, UserID: testUser, SessionID: "sess-1"}
+	guestCart := &model.Cart{ID: 2, SessionID: "sess-2"}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		cart        *model.Cart
+		expectedErr error
+	}{
+		{name: "Owner", ctx: userCtx(), cart: userCart},
+		{name: "Anonymous", ctx: context.Background(), cart: userCart, expectedErr: ErrUnauthenticated},
+		{name: "Other User", ctx: auth.WithPrincipal(context.Background(), auth.Principal{UserID: "user-2"}), cart: userCart, expectedErr: ErrForbidden},
+		{name: "Guest With Owner Session", ctx: auth.WithPrincipal(context.Background(), auth.Principal{SessionID: "sess-1"}), cart: userCart, expectedErr: ErrForbidden},
+		{name: "Guest Session", ctx: auth.WithPrincipal(context.Background(), auth.Principal{SessionID: "sess-2"}), cart: guestCart},
+		{name: "Other Guest", ctx: auth.WithPrincipal(context.Background(), auth.Principal{SessionID: "sess-3"}), cart: guestCart, expectedErr: ErrForbidden},
+		{name: "User Without Session", ctx: userCtx(), cart: guestCart, expectedErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCartRepo)
+			mockRepo.On("GetCart", mock.Anything, tt.cart.ID).Return(tt.cart, nil)
+
+			service := NewCartService(mockRepo)
+			cart, err := service.GetCart(tt.ctx, tt.cart.ID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, cart)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.cart, cart)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClaimLegacyCart(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	mockRepo.On("GetCart", mock.Anything, 3).Return(&model.Cart{ID: 3}, nil)
+	mockRepo.On("ClaimCart", mock.Anything, 3, testUser, "").Return(true, nil)
+
+	service := NewCartService(mockRepo)
+	cart, err := service.GetCart(userCtx(), 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, testUser, cart.UserID)
+	mockRepo.AssertExpectations(t)
+
+	mockRepo = new(MockCartRepo)
+	mockRepo.On("GetCart", mock.Anything, 3).Return(&model.Cart{ID: 3}, nil)
+	mockRepo.On("ClaimCart", mock.Anything, 3, "", "sess-1").Return(false, nil)
+
+	service = NewCartService(mockRepo)
+	_, err = service.GetCart(auth.WithPrincipal(context.Background(), auth.Principal{SessionID: "sess-1"}), 3)
+
+	assert.ErrorIs(t, err, ErrForbidden, "claimed by someone else first")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateCartAsGuest(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	mockRepo.On("CreateCart", mock.Anything, model.Cart{SessionID: "sess-1"}).Return(&model.Cart{ID: 7, SessionID: "sess-1"}, nil)
+
+	service := NewCartService(mockRepo)
+	cart, err := service.CreateCart(auth.WithPrincipal(context.Background(), auth.Principal{SessionID: "sess-1"}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, cart.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateItemForbidden(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: "user-2"}, nil)
+
+	service := NewCartService(mockRepo)
+	updated, err := service.UpdateItem(userCtx(), model.CartItem{Id: 10, CartId: 1, Quantity: 2})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.Nil(t, updated)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMergeCart(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: testUser, SessionID: "sess-1"})
+	userCart := &model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}
+	guestCart := &model.Cart{ID: 2, SessionID: "sess-1", Status: model.CartOpen}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		target := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+			{Id: 1, CartId: 1, Product: "Apple", Price: usd("10.00"), Quantity: 1},
+			{Id: 2, CartId: 1, Product: "Pear", Price: usd("5.00"), Quantity: 1},
+		}}
+		guest := &model.Cart{ID: 2, SessionID: "sess-1", Items: []model.CartItem{
+			{Id: 7, CartId: 2, Product: "Apple", Price: usd("12.00"), Quantity: 2},
+			{Id: 8, CartId: 2, Product: "Kiwi", Price: usd("3.00"), Quantity: 4},
+		}}
+		merged := &model.Cart{ID: 1, UserID: testUser}
+
+		mockRepo.On("LockCart", mock.Anything, 1).Return(userCart, nil)
+		mockRepo.On("LockCart", mock.Anything, 2).Return(guestCart, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(target, nil).Once()
+		mockRepo.On("GetCart", mock.Anything, 2).Return(guest, nil)
+		mockRepo.On("ReplaceItem", mock.Anything, model.CartItem{Id: 1, CartId: 1, Product: "Apple", Price: usd("12.00"), Quantity: 3}).
+			Return(&model.CartItem{}, nil)
+		mockRepo.On("CreateItem", mock.Anything, model.CartItem{CartId: 1, Product: "Kiwi", Price: usd("3.00"), Quantity: 4}).
+			Return(&model.CartItem{Id: 9, CartId: 1, Product: "Kiwi"}, nil)
+		mockRepo.On("DeleteCart", mock.Anything, 2).Return(nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(merged, nil).Once()
+
+		service := NewCartService(mockRepo)
+		result, err := service.MergeCart(ctx, 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, merged, result.Cart)
+		assert.Empty(t, result.Conflicts)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Keeps Newer Price", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		target := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+			{Id: 9, CartId: 1, Product: "Apple", Price: usd("10.00"), Quantity: 1},
+		}}
+		guest := &model.Cart{ID: 2, SessionID: "sess-1", Items: []model.CartItem{
+			{Id: 7, CartId: 2, Product: "Apple", Price: usd("12.00"), Quantity: 2},
+		}}
+
+		mockRepo.On("LockCart", mock.Anything, 1).Return(userCart, nil)
+		mockRepo.On("LockCart", mock.Anything, 2).Return(guestCart, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(target, nil)
+		mockRepo.On("GetCart", mock.Anything, 2).Return(guest, nil)
+		mockRepo.On("ReplaceItem", mock.Anything, model.CartItem{Id: 9, CartId: 1, Product: "Apple", Price: usd("10.00"), Quantity: 3}).
+			Return(&model.CartItem{}, nil)
+		mockRepo.On("DeleteCart", mock.Anything, 2).Return(nil)
+
+		service := NewCartService(mockRepo)
+		_, err := service.MergeCart(ctx, 1, 2)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cart Limit Conflict", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		target := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+			{Id: 1, Product: "A", Quantity: 1}, {Id: 2, Product: "B", Quantity: 1}, {Id: 3, Product: "C", Quantity: 1},
+			{Id: 4, Product: "D", Quantity: 1}, {Id: 5, Product: "E", Quantity: 1},
+		}}
+		guest := &model.Cart{ID: 2, SessionID: "sess-1", Items: []model.CartItem{
+			{Id: 7, CartId: 2, Product: "F", Price: usd("1.00"), Quantity: 2},
+		}}
+
+		mockRepo.On("LockCart", mock.Anything, 1).Return(userCart, nil)
+		mockRepo.On("LockCart", mock.Anything, 2).Return(guestCart, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(target, nil)
+		mockRepo.On("GetCart", mock.Anything, 2).Return(guest, nil)
+		mockRepo.On("DeleteCart", mock.Anything, 2).Return(nil)
+
+		service := NewCartService(mockRepo)
+		result, err := service.MergeCart(ctx, 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.MergeConflict{{Product: "F", Quantity: 2, Reason: "cart limit reached: max 5 distinct products"}}, result.Conflicts)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			ctx         context.Context
+			target      *model.Cart
+			guest       *model.Cart
+			expectedErr error
+		}{
+			{name: "Guest Caller", ctx: auth.WithPrincipal(context.Background(), auth.Principal{SessionID: "sess-1"}), expectedErr: ErrMergeTargetGuest},
+			{name: "Anonymous", ctx: context.Background(), expectedErr: ErrUnauthenticated},
+			{name: "Guest Cart Owned By User", ctx: ctx, target: userCart, guest: &model.Cart{ID: 2, UserID: "user-2"}, expectedErr: ErrMergeNotGuest},
+			{name: "Target Is Guest Cart", ctx: ctx, target: &model.Cart{ID: 1, SessionID: "sess-1"}, guest: guestCart, expectedErr: ErrMergeTargetGuest},
+			{name: "Foreign Session", ctx: ctx, target: userCart, guest: &model.Cart{ID: 2, SessionID: "sess-9"}, expectedErr: ErrForbidden},
+			{name: "Guest Cart Missing", ctx: ctx, target: userCart, expectedErr: ErrCartNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := new(MockCartRepo)
+				if tt.target != nil {
+					mockRepo.On("LockCart", mock.Anything, 1).Return(tt.target, nil)
+					mockRepo.On("LockCart", mock.Anything, 2).Return(tt.guest, nil)
+				}
+
+				service := NewCartService(mockRepo)
+				result, err := service.MergeCart(tt.ctx, 1, 2)
+
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+				mockRepo.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		expectedErr error
+	}{
+		{name: "No Precondition", ctx: userCtx()},
+		{name: "Matching", ctx: WithExpectedVersion(userCtx(), 3)},
+		{name: "Stale", ctx: WithExpectedVersion(userCtx(), 2), expectedErr: ErrVersionMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCartRepo)
+			item := model.CartItem{Id: 10, CartId: 1}
+			mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen, Version: 3}, nil)
+			if tt.expectedErr == nil {
+				mockRepo.On("ItemExists", mock.Anything, 10).Return(true, nil)
+				mockRepo.On("DeleteItem", mock.Anything, item).Return(nil)
+			}
+
+			service := NewCartService(mockRepo)
+			err := service.DeleteItem(tt.ctx, item)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetPrice(t *testing.T) {
 	tests := []struct {
 		name           string
 		cartID         int
 		mockReturnCart *model.Cart
 		mockReturnErr  error
-		expectedPrice  float64
+		expectedPrice  string
 		expectedDisc   int
 		expectError    bool
 	}{
@@ -197,13 +591,14 @@ func TestGetPrice(t *testing.T) {
 			name:   "No Discount",
 			cartID: 1,
 			mockReturnCart: &model.Cart{
-				ID: 1,
+				ID:     1,
+				UserID: testUser,
 				Items: []model.CartItem{
-					{Price: 100}, {Price: 200},
+					{Price: usd("100"), Quantity: 1}, {Price: usd("200"), Quantity: 1},
 				},
 			},
 			mockReturnErr: nil,
-			expectedPrice: 300.0,
+			expectedPrice: "300.00",
 			expectedDisc:  0,
 			expectError:   false,
 		},
@@ -211,13 +606,14 @@ func TestGetPrice(t *testing.T) {
 			name:   "Quantity Discount 5%",
 			cartID: 2,
 			mockReturnCart: &model.Cart{
-				ID: 2,
+				ID:     2,
+				UserID: testUser,
 				Items: []model.CartItem{
-					{Price: 100}, {Price: 100}, {Price: 100}, {Price: 100},
+					{Price: usd("100"), Quantity: 1}, {Price: usd("100"), Quantity: 1}, {Price: usd("100"), Quantity: 1}, {Price: usd("100"), Quantity: 1},
 				},
 			},
 			mockReturnErr: nil,
-			expectedPrice: 380.0,
+			expectedPrice: "380.00",
 			expectedDisc:  5,
 			expectError:   false,
 		},
@@ -225,22 +621,38 @@ func TestGetPrice(t *testing.T) {
 			name:   "Amount Discount 10%",
 			cartID: 3,
 			mockReturnCart: &model.Cart{
-				ID: 3,
+				ID:     3,
+				UserID: testUser,
 				Items: []model.CartItem{
-					{Price: 6000},
+					{Price: usd("6000"), Quantity: 1},
 				},
 			},
 			mockReturnErr: nil,
-			expectedPrice: 5400.0,
+			expectedPrice: "5400.00",
 			expectedDisc:  10,
 			expectError:   false,
 		},
+		{
+			name:   "Units Discount 5%",
+			cartID: 4,
+			mockReturnCart: &model.Cart{
+				ID:     4,
+				UserID: testUser,
+				Items: []model.CartItem{
+					{Price: usd("100"), Quantity: 4},
+				},
+			},
+			mockReturnErr: nil,
+			expectedPrice: "380.00",
+			expectedDisc:  5,
+			expectError:   false,
+		},
 		{
 			name:           "Repo Error",
 			cartID:         5,
 			mockReturnCart: nil,
 			mockReturnErr:  errors.New("db error"),
-			expectedPrice:  0,
+			expectedPrice:  "",
 			expectedDisc:   0,
 			expectError:    true,
 		},
@@ -250,17 +662,17 @@ func TestGetPrice(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockCartRepo)
 
-			mockRepo.On("GetCart", tt.cartID).Return(tt.mockReturnCart, tt.mockReturnErr)
+			mockRepo.On("GetCart", mock.Anything, tt.cartID).Return(tt.mockReturnCart, tt.mockReturnErr)
 
 			service := NewCartService(mockRepo)
-			gotPrice, err := service.GetPrice(tt.cartID)
+			gotPrice, err := service.GetPrice(userCtx(), tt.cartID)
 
 			if tt.expectError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, gotPrice)
-				assert.Equal(t, tt.expectedPrice, gotPrice.FinalPrice)
+				assert.Equal(t, tt.expectedPrice, gotPrice.FinalPrice.String())
 				assert.Equal(t, tt.expectedDisc, gotPrice.DiscountPercent)
 			}
 
@@ -268,3 +680,384 @@ func TestGetPrice(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPriceRounding(t *testing.T) {
+	cart := &model.Cart{
+		ID:     1,
+		UserID: testUser,
+		Items: []model.CartItem{
+			{Price: usd("2.50"), Quantity: 3}, {Price: usd("2.60"), Quantity: 1},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		mode             money.RoundingMode
+		expectedDiscount string
+		expectedFinal    string
+	}{
+		{name: "Half Up", mode: money.RoundHalfUp, expectedDiscount: "0.51", expectedFinal: "9.59"},
+		{name: "Half Even", mode: money.RoundHalfEven, expectedDiscount: "0.50", expectedFinal: "9.60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCartRepo)
+			mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+
+			service := NewCartService(mockRepo, WithRounding(tt.mode))
+			gotPrice, err := service.GetPrice(userCtx(), 1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "10.10", gotPrice.TotalPrice.String())
+			assert.Equal(t, tt.expectedDiscount, gotPrice.DiscountAmount.String())
+			assert.Equal(t, tt.expectedFinal, gotPrice.FinalPrice.String())
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+type MockDiscountRepo struct {
+	mock.Mock
+}
+
+func (m *MockDiscountRepo) ListActiveRules(ctx context.Context) ([]model.DiscountRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DiscountRule), args.Error(1)
+}
+
+func TestGetPriceWithDiscountRules(t *testing.T) {
+	cart := &model.Cart{
+		ID:     1,
+		UserID: testUser,
+		Items:  []model.CartItem{{Product: "Socks", Price: usd("10.00"), Quantity: 6}},
+	}
+	rules := []model.DiscountRule{
+		{Name: "socks_3_for_2", Kind: "buy_x_get_y", Priority: 1, Params: []byte(`{"product": "Socks", "buy": 2, "free": 1}`)},
+		{Name: "five_off", Kind: "fixed_amount", Priority: 2, Params: []byte(`{"amount": "5.00"}`)},
+	}
+
+	t.Run("Stacked Rules", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRules := new(MockDiscountRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRules.On("ListActiveRules", mock.Anything).Return(rules, nil)
+
+		service := NewCartService(mockRepo, WithDiscountRules(mockRules, pricing.StrategyStack))
+		gotPrice, err := service.GetPrice(userCtx(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "60.00", gotPrice.TotalPrice.String())
+		assert.Equal(t, []model.AppliedDiscount{
+			{Rule: "socks_3_for_2", Amount: usd("20.00")},
+			{Rule: "five_off", Amount: usd("5.00")},
+		}, gotPrice.Discounts)
+		assert.Equal(t, "25.00", gotPrice.DiscountAmount.String())
+		assert.Equal(t, "35.00", gotPrice.FinalPrice.String())
+		mockRepo.AssertExpectations(t)
+		mockRules.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Rule", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRules := new(MockDiscountRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRules.On("ListActiveRules", mock.Anything).Return([]model.DiscountRule{{Name: "broken", Kind: "unknown"}}, nil)
+
+		service := NewCartService(mockRepo, WithDiscountRules(mockRules, pricing.StrategyBest))
+		gotPrice, err := service.GetPrice(userCtx(), 1)
+
+		assert.Error(t, err)
+		assert.Nil(t, gotPrice)
+	})
+}
+
+type MockCouponRepo struct {
+	mock.Mock
+}
+
+func (m *MockCouponRepo) GetCoupon(ctx context.Context, code string) (*model.Coupon, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepo) ListCartCoupons(ctx context.Context, cartID int) ([]model.Coupon, error) {
+	args := m.Called(ctx, cartID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Coupon), args.Error(1)
+}
+
+func (m *MockCouponRepo) AttachCoupon(ctx context.Context, cartID int, code string) error {
+	args := m.Called(ctx, cartID, code)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepo) DetachCoupon(ctx context.Context, cartID int, code string) error {
+	args := m.Called(ctx, cartID, code)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepo) RedeemCoupon(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func TestApplyCoupon(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+	limit := 10
+	cart := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{{Product: "Shoes", Price: usd("80.00"), Quantity: 1}}}
+
+	tests := []struct {
+		name         string
+		coupon       *model.Coupon
+		applied      []model.Coupon
+		expectAttach bool
+		expectedErr  error
+	}{
+		{
+			name:         "Success",
+			coupon:       &model.Coupon{Code: "SAVE10", Percent: 10, Active: true, StartsAt: &yesterday, ExpiresAt: &tomorrow},
+			expectAttach: true,
+		},
+		{
+			name:        "Inactive",
+			coupon:      &model.Coupon{Code: "SAVE10", Percent: 10},
+			expectedErr: ErrCouponNotActive,
+		},
+		{
+			name:        "Not Started",
+			coupon:      &model.Coupon{Code: "SAVE10", Percent: 10, Active: true, StartsAt: &tomorrow},
+			expectedErr: ErrCouponNotActive,
+		},
+		{
+			name:        "Expired",
+			coupon:      &model.Coupon{Code: "SAVE10", Percent: 10, Active: true, ExpiresAt: &yesterday},
+			expectedErr: ErrCouponExpired,
+		},
+		{
+			name:        "Below Minimum",
+			coupon:      &model.Coupon{Code: "SAVE10", Percent: 10, Active: true, MinTotal: usd("100.00")},
+			expectedErr: ErrCouponMinTotal,
+		},
+		{
+			name:        "Usage Limit",
+			coupon:      &model.Coupon{Code: "SAVE10", Percent: 10, Active: true, UsageLimit: &limit, UsedCount: 10},
+			expectedErr: ErrCouponUsageLimit,
+		},
+		{
+			name:        "Other Currency",
+			coupon:      &model.Coupon{Code: "SAVE10", Amount: money.MustParse("10.00", "EUR"), Active: true},
+			expectedErr: ErrCouponCurrency,
+		},
+		{
+			name:         "Percentage In Other Currency",
+			coupon:       &model.Coupon{Code: "SAVE10", Percent: 10, Amount: money.New(0, "EUR"), Active: true},
+			expectAttach: true,
+		},
+		{
+			name:        "Already Applied",
+			coupon:      &model.Coupon{Code: "SAVE10", Percent: 10, Active: true, Stackable: true},
+			applied:     []model.Coupon{{Code: "SAVE10", Stackable: true}},
+			expectedErr: ErrCouponAlreadyApplied,
+		},
+		{
+			name:        "Not Stackable",
+			coupon:      &model.Coupon{Code: "SAVE10", Percent: 10, Active: true},
+			applied:     []model.Coupon{{Code: "FREESHIP", Stackable: true}},
+			expectedErr: ErrCouponNotStackable,
+		},
+		{
+			name:         "Both Stackable",
+			coupon:       &model.Coupon{Code: "SAVE10", Percent: 10, Active: true, Stackable: true},
+			applied:      []model.Coupon{{Code: "FIVEOFF", Stackable: true}},
+			expectAttach: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockCartRepo)
+			mockCoupons := new(MockCouponRepo)
+			mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+			mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+			mockCoupons.On("GetCoupon", mock.Anything, "SAVE10").Return(tt.coupon, nil)
+			mockCoupons.On("ListCartCoupons", mock.Anything, 1).Return(tt.applied, nil).Maybe()
+			if tt.expectAttach {
+				mockCoupons.On("AttachCoupon", mock.Anything, 1, "SAVE10").Return(nil)
+			}
+
+			service := NewCartService(mockRepo, WithCoupons(mockCoupons), WithClock(func() time.Time { return now }))
+			coupon, err := service.ApplyCoupon(userCtx(), 1, " save10 ")
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, coupon)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "SAVE10", coupon.Code)
+			}
+			mockRepo.AssertExpectations(t)
+			mockCoupons.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRemoveCoupon(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	mockCoupons := new(MockCouponRepo)
+	mockRepo.On("LockCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen}, nil)
+	mockCoupons.On("DetachCoupon", mock.Anything, 1, "SAVE10").Return(nil)
+
+	service := NewCartService(mockRepo, WithCoupons(mockCoupons))
+	err := service.RemoveCoupon(userCtx(), 1, "save10")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockCoupons.AssertExpectations(t)
+}
+
+func TestGetPriceWithCoupons(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	cart := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{{Product: "Shoes", Price: usd("1000.00"), Quantity: 6}}}
+	coupons := []model.Coupon{
+		{Code: "TENOFF", Amount: usd("10.00"), Active: true, Stackable: true},
+		{Code: "PCT20", Percent: 20, Active: true, Stackable: true},
+		{Code: "OLD", Percent: 50, Active: true, Stackable: true, ExpiresAt: &yesterday},
+		{Code: "EURO5", Amount: money.MustParse("5.00", "EUR"), Active: true, Stackable: true},
+	}
+
+	mockRepo := new(MockCartRepo)
+	mockCoupons := new(MockCouponRepo)
+	mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+	mockCoupons.On("ListCartCoupons", mock.Anything, 1).Return(coupons, nil)
+
+	service := NewCartService(mockRepo, WithCoupons(mockCoupons), WithClock(func() time.Time { return now }))
+	gotPrice, err := service.GetPrice(userCtx(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "6000.00", gotPrice.TotalPrice.String())
+	assert.Equal(t, "600.00", gotPrice.DiscountAmount.String())
+	assert.Equal(t, []model.AppliedCoupon{
+		{Code: "TENOFF", Amount: usd("10.00")},
+		{Code: "PCT20", Amount: usd("1078.00")},
+	}, gotPrice.Coupons)
+	assert.Equal(t, "4312.00", gotPrice.FinalPrice.String())
+	mockRepo.AssertExpectations(t)
+	mockCoupons.AssertExpectations(t)
+}
+
+type memTxKey struct{}
+
+type memTx struct {
+	locked []*sync.Mutex
+}
+
+// memoryCartRepo emulates the row lock taken by LockCart: the lock is held
+// until the surrounding WithTx returns.
+type memoryCartRepo struct {
+	MockCartRepo
+	mu        sync.Mutex
+	cartLocks map[int]*sync.Mutex
+	items     map[int][]model.CartItem
+	nextID    int
+}
+
+func newMemoryCartRepo(cartIDs ...int) *memoryCartRepo {
+	r := &memoryCartRepo{cartLocks: map[int]*sync.Mutex{}, items: map[int][]model.CartItem{}}
+	for _, id := range cartIDs {
+		r.cartLocks[id] = &sync.Mutex{}
+		r.items[id] = nil
+	}
+	return r
+}
+
+func (r *memoryCartRepo) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	tx := &memTx{}
+	defer func() {
+		for _, l := range tx.locked {
+			l.Unlock()
+		}
+	}()
+	return fn(context.WithValue(ctx, memTxKey{}, tx))
+}
+
+func (r *memoryCartRepo) LockCart(ctx context.Context, id int) (*model.Cart, error) {
+	tx, ok := ctx.Value(memTxKey{}).(*memTx)
+	if !ok {
+		return nil, errors.New("LockCart called outside of a transaction")
+	}
+	r.mu.Lock()
+	l, exists := r.cartLocks[id]
+	r.mu.Unlock()
+	if !exists {
+		return nil, nil
+	}
+	l.Lock()
+	tx.locked = append(tx.locked, l)
+	return &model.Cart{ID: id, UserID: testUser, Status: model.CartOpen}, nil
+}
+
+func (r *memoryCartRepo) GetCart(_ context.Context, id int) (*model.Cart, error) {
+	r.mu.Lock()
+	items := append([]model.CartItem(nil), r.items[id]...)
+	r.mu.Unlock()
+	runtime.Gosched()
+	return &model.Cart{ID: id, UserID: testUser, Items: items}, nil
+}
+
+func (r *memoryCartRepo) CreateItem(_ context.Context, item model.CartItem) (*model.CartItem, error) {
+	runtime.Gosched()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	item.Id = r.nextID
+	r.items[item.CartId] = append(r.items[item.CartId], item)
+	return &item, nil
+}
+
+func TestCreateItemConcurrentCartLimit(t *testing.T) {
+	const workers = 50
+	repo := newMemoryCartRepo(1)
+	service := NewCartService(repo)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var succeeded, rejected int
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			item := model.CartItem{CartId: 1, Product: fmt.Sprintf("product-%d", i), Price: usd("1.00"), Quantity: 1}
+			_, err := service.CreateItem(userCtx(), item)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrReachCartLimit):
+				rejected++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, 5, succeeded)
+	assert.Equal(t, workers-5, rejected)
+	assert.Len(t, repo.items[1], 5)
+}
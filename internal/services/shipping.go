@@ -0,0 +1,117 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/pricing"
+	"cart-api/internal/shipping"
+	"cart-api/internal/tracing"
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func WithShipping(calculators []shipping.Calculator) Option {
+	return func(s *CartService) {
+		s.shipping = calculators
+	}
+}
+
+// ShippingOptions quotes every shipping method that can deliver the cart.
+func (s *CartService) ShippingOptions(ctx context.Context, cartID int) (_ []model.ShippingOption, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.ShippingOptions", attribute.Int("cart.id", cartID))
+	defer func() { tracing.End(span, err) }()
+	cart, err := s.CartRepo.GetCart(ctx, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	if err = s.authorize(ctx, cart); err != nil {
+		return nil, err
+	}
+	in, price, err := s.discountedPrice(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+	parcel := shipping.NewParcel(cart.Items, price.FinalPrice, in.Convert)
+	options := make([]model.ShippingOption, 0, len(s.shipping))
+	for _, calculator := range s.shipping {
+		quote, ok := calculator.Quote(parcel)
+		if !ok {
+			continue
+		}
+		options = append(options, model.ShippingOption{
+			Method:   calculator.Method(),
+			Name:     calculator.Name(),
+			Price:    quote,
+			Selected: calculator.Method() == cart.ShippingMethod,
+		})
+	}
+	return options, nil
+}
+
+// SelectShipping stores the shipping method that GetPrice and Checkout charge
+// for the cart.
+func (s *CartService) SelectShipping(ctx context.Context, cartID int, method string) (_ *model.ShippingOption, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.SelectShipping", attribute.Int("cart.id", cartID), attribute.String("shipping.method", method))
+	defer func() { tracing.End(span, err) }()
+	calculator := s.shippingMethod(method)
+	if calculator == nil {
+		return nil, ErrUnknownShippingMethod
+	}
+	var option *model.ShippingOption
+	err = s.CartRepo.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOpenCart(ctx, cartID); err != nil {
+			return err
+		}
+		cart, err := s.CartRepo.GetCart(ctx, cartID)
+		if err != nil {
+			return fmt.Errorf("failed to get cart: %w", err)
+		}
+		in, price, err := s.discountedPrice(ctx, cart)
+		if err != nil {
+			return err
+		}
+		quote, ok := calculator.Quote(shipping.NewParcel(cart.Items, price.FinalPrice, in.Convert))
+		if !ok {
+			return ErrShippingUnavailable
+		}
+		if err = s.CartRepo.SetShippingMethod(ctx, cartID, method); err != nil {
+			return fmt.Errorf("failed to set shipping method: %w", err)
+		}
+		option = &model.ShippingOption{Method: calculator.Method(), Name: calculator.Name(), Price: quote, Selected: true}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return option, nil
+}
+
+func (s *CartService) shippingMethod(method string) shipping.Calculator {
+	for _, calculator := range s.shipping {
+		if calculator.Method() == method {
+			return calculator
+		}
+	}
+	return nil
+}
+
+// applyShipping adds the selected shipping method to the price. It runs after
+// applyTax, so the goods total still excludes shipping when tax is computed.
+func (s *CartService) applyShipping(cart *model.Cart, in pricing.Input, price *model.Price) error {
+	if cart.ShippingMethod == "" {
+		return nil
+	}
+	calculator := s.shippingMethod(cart.ShippingMethod)
+	if calculator == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownShippingMethod, cart.ShippingMethod)
+	}
+	quote, ok := calculator.Quote(shipping.NewParcel(in.Items, price.FinalPrice, in.Convert))
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrShippingUnavailable, cart.ShippingMethod)
+	}
+	price.Shipping = &model.AppliedShipping{Method: cart.ShippingMethod, Amount: quote}
+	price.FinalPrice = price.FinalPrice.Add(quote)
+	price.Subtotal = price.Subtotal.Add(quote)
+	price.GrandTotal = price.GrandTotal.Add(quote)
+	return nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/shipping"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testShippingMethods(t *testing.T) []shipping.Calculator {
+	t.Helper()
+	calculators, err := shipping.Parse(`[
+		{"method": "standard", "kind": "weight_tier", "free_over": "100.00",
+		 "tiers": [{"max_grams": 1000, "price": "4.99"}, {"max_grams": 5000, "price": "9.99"}]},
+		{"method": "express", "kind": "flat_rate", "price": "24.99"}
+	]`)
+	require.NoError(t, err)
+	return calculators
+}
+
+func TestShippingOptions(t *testing.T) {
+	t.Run("Quotes Available Methods", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, ShippingMethod: "express", Items: []model.CartItem{
+			{Id: 1, CartId: 1, Product: "Kettle", Price: usd("30.00"), Quantity: 2, Dimensions: model.Dimensions{WeightGrams: 1500}},
+		}}, nil)
+
+		service := NewCartService(mockRepo, WithShipping(testShippingMethods(t)))
+		options, err := service.ShippingOptions(userCtx(), 1)
+
+		require.NoError(t, err)
+		require.Len(t, options, 2)
+		assert.Equal(t, "standard", options[0].Method)
+		assert.Equal(t, "9.99", options[0].Price.String())
+		assert.False(t, options[0].Selected)
+		assert.Equal(t, "24.99", options[1].Price.String())
+		assert.True(t, options[1].Selected)
+	})
+
+	t.Run("Skips Methods That Cannot Ship", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+			{Id: 1, CartId: 1, Product: "Anvil", Price: usd("80.00"), Quantity: 1, Dimensions: model.Dimensions{WeightGrams: 40000}},
+		}}, nil)
+
+		service := NewCartService(mockRepo, WithShipping(testShippingMethods(t)))
+		options, err := service.ShippingOptions(userCtx(), 1)
+
+		require.NoError(t, err)
+		require.Len(t, options, 1)
+		assert.Equal(t, "express", options[0].Method)
+	})
+}
+
+func TestSelectShipping(t *testing.T) {
+	cart := &model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen, Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Book", Price: usd("120.00"), Quantity: 1, Dimensions: model.Dimensions{WeightGrams: 500}},
+	}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(cart, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockRepo.On("SetShippingMethod", mock.Anything, 1, "standard").Return(nil)
+
+		service := NewCartService(mockRepo, WithShipping(testShippingMethods(t)))
+		option, err := service.SelectShipping(userCtx(), 1, "standard")
+
+		require.NoError(t, err)
+		assert.Equal(t, "0.00", option.Price.String())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Method", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+
+		service := NewCartService(mockRepo, WithShipping(testShippingMethods(t)))
+		_, err := service.SelectShipping(userCtx(), 1, "drone")
+
+		assert.ErrorIs(t, err, ErrUnknownShippingMethod)
+		mockRepo.AssertNotCalled(t, "SetShippingMethod", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unavailable Method", func(t *testing.T) {
+		heavy := &model.Cart{ID: 1, UserID: testUser, Status: model.CartOpen, Items: []model.CartItem{
+			{Id: 1, CartId: 1, Product: "Anvil", Price: usd("80.00"), Quantity: 1, Dimensions: model.Dimensions{WeightGrams: 40000}},
+		}}
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("LockCart", mock.Anything, 1).Return(heavy, nil)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(heavy, nil)
+
+		service := NewCartService(mockRepo, WithShipping(testShippingMethods(t)))
+		_, err := service.SelectShipping(userCtx(), 1, "standard")
+
+		assert.ErrorIs(t, err, ErrShippingUnavailable)
+		mockRepo.AssertNotCalled(t, "SetShippingMethod", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetPriceWithShipping(t *testing.T) {
+	mockRepo := new(MockCartRepo)
+	mockRepo.On("GetCart", mock.Anything, 1).Return(&model.Cart{ID: 1, UserID: testUser, ShippingMethod: "standard", Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Lamp", Price: usd("40.00"), Quantity: 1, Dimensions: model.Dimensions{WeightGrams: 800, LengthMM: 300, WidthMM: 200, HeightMM: 200}},
+	}}, nil)
+
+	service := NewCartService(mockRepo, WithShipping(testShippingMethods(t)))
+	price, err := service.GetPrice(userCtx(), 1)
+
+	require.NoError(t, err)
+	require.NotNil(t, price.Shipping)
+	assert.Equal(t, "9.99", price.Shipping.Amount.String())
+	assert.Equal(t, "49.99", price.FinalPrice.String())
+	assert.Equal(t, "49.99", price.GrandTotal.String())
+}
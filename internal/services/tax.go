@@ -0,0 +1,75 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/pricing"
+	"cart-api/pkg/money"
+	"context"
+	"fmt"
+	"strings"
+)
+
+type TaxRateRepository interface {
+	ListTaxRates(context.Context, string) ([]model.TaxRate, error)
+}
+
+// WithTax adds tax to prices. defaultRegion is used when the request does not
+// name a region; when it is empty such prices carry no tax.
+func WithTax(repo TaxRateRepository, mode pricing.TaxMode, defaultRegion string) Option {
+	return func(s *CartService) {
+		s.taxRates = repo
+		s.taxMode = mode
+		s.taxRegion = NormalizeRegion(defaultRegion)
+	}
+}
+
+type taxRegionKey struct{}
+
+// WithTaxRegion makes prices calculated with ctx use the tax rates of region.
+func WithTaxRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, taxRegionKey{}, NormalizeRegion(region))
+}
+
+func TaxRegion(ctx context.Context) (string, bool) {
+	region, ok := ctx.Value(taxRegionKey{}).(string)
+	return region, ok && region != ""
+}
+
+func NormalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
+
+func (s *CartService) applyTax(ctx context.Context, in pricing.Input, price *model.Price) error {
+	price.Subtotal = price.FinalPrice
+	price.TaxTotal = money.New(0, price.FinalPrice.Currency)
+	price.GrandTotal = price.FinalPrice
+	if s.taxRates == nil {
+		return nil
+	}
+	region, requested := TaxRegion(ctx)
+	if !requested {
+		region = s.taxRegion
+	}
+	if region == "" {
+		return nil
+	}
+	rates, err := s.taxRates.ListTaxRates(ctx, region)
+	if err != nil {
+		return fmt.Errorf("failed to get tax rates: %w", err)
+	}
+	if len(rates) == 0 && requested {
+		return fmt.Errorf("%w: %s", ErrUnknownTaxRegion, region)
+	}
+	price.Region = region
+	price.TaxMode = string(s.taxMode)
+	price.Taxes = s.engine.Tax(in, price.TotalPrice.Sub(price.FinalPrice), rates, s.taxMode)
+	for _, line := range price.Taxes {
+		price.TaxTotal = price.TaxTotal.Add(line.Amount)
+	}
+	if s.taxMode == pricing.TaxInclusive {
+		price.Subtotal = price.FinalPrice.Sub(price.TaxTotal)
+	} else {
+		price.GrandTotal = price.FinalPrice.Add(price.TaxTotal)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/pricing"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTaxRateRepo struct {
+	mock.Mock
+}
+
+func (m *MockTaxRateRepo) ListTaxRates(ctx context.Context, region string) ([]model.TaxRate, error) {
+	args := m.Called(ctx, region)
+	return args.Get(0).([]model.TaxRate), args.Error(1)
+}
+
+func TestGetPriceWithTax(t *testing.T) {
+	cart := &model.Cart{ID: 1, UserID: testUser, Items: []model.CartItem{
+		{Id: 1, CartId: 1, Product: "Shoes", Price: usd("100.00"), Quantity: 1},
+		{Id: 2, CartId: 1, Product: "Bread", Price: usd("10.00"), Quantity: 1, TaxCategory: "food"},
+	}}
+	deRates := []model.TaxRate{
+		{Region: "DE", Category: "standard", Rate: 190000},
+		{Region: "DE", Category: "food", Rate: 70000},
+	}
+
+	t.Run("Default Region Exclusive", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockTax := new(MockTaxRateRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockTax.On("ListTaxRates", mock.Anything, "DE").Return(deRates, nil)
+
+		service := NewCartService(mockRepo, WithTax(mockTax, pricing.TaxExclusive, "de"))
+		price, err := service.GetPrice(userCtx(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "DE", price.Region)
+		assert.Len(t, price.Taxes, 2)
+		assert.Equal(t, "110.00", price.Subtotal.String())
+		assert.Equal(t, "19.70", price.TaxTotal.String())
+		assert.Equal(t, "129.70", price.GrandTotal.String())
+	})
+
+	t.Run("Requested Region Inclusive", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockTax := new(MockTaxRateRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockTax.On("ListTaxRates", mock.Anything, "DE").Return(deRates, nil)
+
+		service := NewCartService(mockRepo, WithTax(mockTax, pricing.TaxInclusive, "US-NY"))
+		price, err := service.GetPrice(WithTaxRegion(userCtx(), "de"), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "16.62", price.TaxTotal.String())
+		assert.Equal(t, "93.38", price.Subtotal.String())
+		assert.Equal(t, "110.00", price.GrandTotal.String())
+	})
+
+	t.Run("Unknown Region", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockTax := new(MockTaxRateRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+		mockTax.On("ListTaxRates", mock.Anything, "XX").Return([]model.TaxRate(nil), nil)
+
+		service := NewCartService(mockRepo, WithTax(mockTax, pricing.TaxExclusive, ""))
+		_, err := service.GetPrice(WithTaxRegion(userCtx(), "xx"), 1)
+
+		assert.ErrorIs(t, err, ErrUnknownTaxRegion)
+	})
+
+	t.Run("No Region", func(t *testing.T) {
+		mockRepo := new(MockCartRepo)
+		mockRepo.On("GetCart", mock.Anything, 1).Return(cart, nil)
+
+		service := NewCartService(mockRepo, WithTax(new(MockTaxRateRepo), pricing.TaxExclusive, ""))
+		price, err := service.GetPrice(userCtx(), 1)
+
+		assert.NoError(t, err)
+		assert.Empty(t, price.Taxes)
+		assert.Equal(t, "110.00", price.GrandTotal.String())
+	})
+}
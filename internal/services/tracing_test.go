@@ -0,0 +1,31 @@
+package services
+
+import (
+	"cart-api/internal/repository/Cart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestServiceSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	mockRepo := new(MockCartRepo)
+	mockRepo.On("GetCart", mock.Anything, 9).Return(nil, &Cart.ErrCartNotFound{ID: 9})
+
+	_, err := NewCartService(mockRepo).GetPrice(userCtx(), 9)
+
+	assert.Error(t, err)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "CartService.GetPrice", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("cart.id", 9))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
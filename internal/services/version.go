@@ -0,0 +1,16 @@
+package services
+
+import "context"
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion makes cart mutations in ctx fail with ErrVersionMismatch
+// unless the cart is still at the given version when it is locked.
+func WithExpectedVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+func ExpectedVersion(ctx context.Context) (int, bool) {
+	version, ok := ctx.Value(expectedVersionKey{}).(int)
+	return version, ok
+}
@@ -0,0 +1,192 @@
+package shipping
+
+import (
+	"cart-api/internal/exchange"
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	KindFlatRate   = "flat_rate"
+	KindWeightTier = "weight_tier"
+)
+
+// volumetricDivisor converts a package volume in mm³ to a billable weight in
+// grams, matching the common 5000 cm³/kg courier rule.
+const volumetricDivisor = 5000
+
+// Parcel is what gets shipped: the cart items and the amount paid for them
+// after discounts. Convert turns configured prices, which are in
+// money.DefaultCurrency, into the currency of Total; methods whose prices it
+// cannot convert are unavailable.
+type Parcel struct {
+	Items   []model.CartItem
+	Total   money.Money
+	Convert exchange.Converter
+}
+
+func NewParcel(items []model.CartItem, total money.Money, convert exchange.Converter) Parcel {
+	return Parcel{Items: items, Total: total, Convert: convert}
+}
+
+// WeightGrams is the billable weight of the parcel: for every unit the larger
+// of its actual and its volumetric weight.
+func (p Parcel) WeightGrams() int {
+	var total int
+	for _, item := range p.Items {
+		d := item.Dimensions
+		volumetric := d.LengthMM * d.WidthMM * d.HeightMM / volumetricDivisor
+		total += max(d.WeightGrams, volumetric) * item.Quantity
+	}
+	return total
+}
+
+type Calculator interface {
+	Method() string
+	Name() string
+	Quote(Parcel) (money.Money, bool)
+}
+
+type methodInfo struct {
+	method string
+	name   string
+}
+
+func (m methodInfo) Method() string { return m.method }
+func (m methodInfo) Name() string   { return m.name }
+
+type FlatRate struct {
+	methodInfo
+	Price money.Money
+}
+
+func (c *FlatRate) Quote(p Parcel) (money.Money, bool) {
+	return p.Convert(c.Price)
+}
+
+type Tier struct {
+	MaxGrams int         `json:"max_grams"`
+	Price    money.Money `json:"price"`
+}
+
+// WeightTier charges the price of the lightest tier the parcel fits in.
+// Parcels heavier than the last tier cannot be shipped with this method.
+type WeightTier struct {
+	methodInfo
+	Tiers []Tier
+}
+
+func (c *WeightTier) Quote(p Parcel) (money.Money, bool) {
+	weight := p.WeightGrams()
+	for _, tier := range c.Tiers {
+		if weight <= tier.MaxGrams {
+			return p.Convert(tier.Price)
+		}
+	}
+	return money.Money{}, false
+}
+
+// FreeOverThreshold ships for free once the parcel total reaches Threshold
+// and otherwise charges what the wrapped calculator quotes.
+type FreeOverThreshold struct {
+	Calculator
+	Threshold money.Money
+}
+
+func (c *FreeOverThreshold) Quote(p Parcel) (money.Money, bool) {
+	price, ok := c.Calculator.Quote(p)
+	if !ok {
+		return price, false
+	}
+	threshold, ok := p.Convert(c.Threshold)
+	if !ok {
+		return money.Money{}, false
+	}
+	if p.Total.Amount >= threshold.Amount {
+		return money.New(0, p.Total.Currency), true
+	}
+	return price, true
+}
+
+type MethodConfig struct {
+	Method   string       `json:"method"`
+	Name     string       `json:"name"`
+	Kind     string       `json:"kind"`
+	Price    money.Money  `json:"price"`
+	Tiers    []Tier       `json:"tiers"`
+	FreeOver *money.Money `json:"free_over"`
+}
+
+// Parse builds calculators from a JSON array of method configs. Prices and
+// thresholds are in money.DefaultCurrency.
+func Parse(raw string) ([]Calculator, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var configs []MethodConfig
+	if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+		return nil, fmt.Errorf("invalid shipping methods: %w", err)
+	}
+	calculators := make([]Calculator, 0, len(configs))
+	seen := make(map[string]struct{}, len(configs))
+	for _, cfg := range configs {
+		if _, ok := seen[cfg.Method]; ok {
+			return nil, fmt.Errorf("shipping method %q: defined twice", cfg.Method)
+		}
+		seen[cfg.Method] = struct{}{}
+		calculator, err := Build(cfg)
+		if err != nil {
+			return nil, err
+		}
+		calculators = append(calculators, calculator)
+	}
+	return calculators, nil
+}
+
+func Build(cfg MethodConfig) (Calculator, error) {
+	if cfg.Method == "" {
+		return nil, fmt.Errorf("shipping method: method is required")
+	}
+	info := methodInfo{method: cfg.Method, name: cfg.Name}
+	if info.name == "" {
+		info.name = cfg.Method
+	}
+	cfg.Price.Currency = money.DefaultCurrency
+	var calculator Calculator
+	switch cfg.Kind {
+	case KindFlatRate:
+		if cfg.Price.IsNegative() {
+			return nil, fmt.Errorf("shipping method %q: price must not be negative", cfg.Method)
+		}
+		calculator = &FlatRate{methodInfo: info, Price: cfg.Price}
+	case KindWeightTier:
+		if len(cfg.Tiers) == 0 {
+			return nil, fmt.Errorf("shipping method %q: at least one tier is required", cfg.Method)
+		}
+		tiers := make([]Tier, len(cfg.Tiers))
+		copy(tiers, cfg.Tiers)
+		for i := range tiers {
+			tiers[i].Price.Currency = money.DefaultCurrency
+		}
+		sort.Slice(tiers, func(i, j int) bool { return tiers[i].MaxGrams < tiers[j].MaxGrams })
+		for _, tier := range tiers {
+			if tier.MaxGrams <= 0 || tier.Price.IsNegative() {
+				return nil, fmt.Errorf("shipping method %q: invalid tier", cfg.Method)
+			}
+		}
+		calculator = &WeightTier{methodInfo: info, Tiers: tiers}
+	default:
+		return nil, fmt.Errorf("shipping method %q: unknown kind %q", cfg.Method, cfg.Kind)
+	}
+	if cfg.FreeOver != nil {
+		if cfg.FreeOver.IsNegative() {
+			return nil, fmt.Errorf("shipping method %q: free_over must not be negative", cfg.Method)
+		}
+		threshold := money.New(cfg.FreeOver.Amount, money.DefaultCurrency)
+		calculator = &FreeOverThreshold{Calculator: calculator, Threshold: threshold}
+	}
+	return calculator, nil
+}
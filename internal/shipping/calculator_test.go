@@ -0,0 +1,105 @@
+package shipping
+
+import (
+	"cart-api/internal/exchange"
+	"cart-api/internal/model"
+	"cart-api/pkg/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.DefaultCurrency)
+}
+
+func usdParcel(items []model.CartItem, total money.Money) Parcel {
+	return NewParcel(items, total, exchange.NewConverter(money.DefaultCurrency, nil, money.RoundHalfUp))
+}
+
+const testMethods = `[
+	{"method": "standard", "name": "Standard", "kind": "weight_tier", "free_over": "100.00",
+	 "tiers": [{"max_grams": 5000, "price": "9.99"}, {"max_grams": 1000, "price": "4.99"}]},
+	{"method": "express", "name": "Express", "kind": "flat_rate", "price": "24.99"}
+]`
+
+func TestParcelWeight(t *testing.T) {
+	parcel := usdParcel([]model.CartItem{
+		{Quantity: 2, Dimensions: model.Dimensions{WeightGrams: 300}},
+		{Quantity: 1, Dimensions: model.Dimensions{WeightGrams: 100, LengthMM: 400, WidthMM: 300, HeightMM: 100}},
+	}, usd("10.00"))
+
+	assert.Equal(t, 600+2400, parcel.WeightGrams())
+}
+
+func TestCalculators(t *testing.T) {
+	calculators, err := Parse(testMethods)
+	require.NoError(t, err)
+	require.Len(t, calculators, 2)
+	standard, express := calculators[0], calculators[1]
+
+	light := []model.CartItem{{Quantity: 1, Dimensions: model.Dimensions{WeightGrams: 800}}}
+	medium := []model.CartItem{{Quantity: 3, Dimensions: model.Dimensions{WeightGrams: 800}}}
+	heavy := []model.CartItem{{Quantity: 10, Dimensions: model.Dimensions{WeightGrams: 800}}}
+
+	tests := []struct {
+		name       string
+		calculator Calculator
+		parcel     Parcel
+		expected   string
+		available  bool
+	}{
+		{name: "first tier", calculator: standard, parcel: usdParcel(light, usd("50.00")), expected: "4.99", available: true},
+		{name: "second tier", calculator: standard, parcel: usdParcel(medium, usd("50.00")), expected: "9.99", available: true},
+		{name: "too heavy", calculator: standard, parcel: usdParcel(heavy, usd("50.00"))},
+		{name: "free over threshold", calculator: standard, parcel: usdParcel(medium, usd("100.00")), expected: "0.00", available: true},
+		{name: "flat rate", calculator: express, parcel: usdParcel(heavy, usd("500.00")), expected: "24.99", available: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := tt.calculator.Quote(tt.parcel)
+			assert.Equal(t, tt.available, ok)
+			if tt.available {
+				assert.Equal(t, tt.expected, price.String())
+			}
+		})
+	}
+}
+
+func TestCalculatorsConvertConfiguredPrices(t *testing.T) {
+	calculators, err := Parse(testMethods)
+	require.NoError(t, err)
+	standard, express := calculators[0], calculators[1]
+	medium := []model.CartItem{{Quantity: 3, Dimensions: model.Dimensions{WeightGrams: 800}}}
+	yen := func(amount string) money.Money { return money.MustParse(amount, "JPY") }
+	rate := model.ExchangeRate{Base: money.DefaultCurrency, Quote: "JPY", Rate: 15_000_000_000}
+	convert := exchange.NewConverter("JPY", &rate, money.RoundHalfUp)
+
+	price, ok := express.Quote(NewParcel(medium, yen("5000"), convert))
+	require.True(t, ok)
+	assert.Equal(t, yen("3749"), price)
+	price, ok = standard.Quote(NewParcel(medium, yen("14000"), convert))
+	require.True(t, ok)
+	assert.Equal(t, yen("1499"), price)
+	price, ok = standard.Quote(NewParcel(medium, yen("15000"), convert))
+	require.True(t, ok)
+	assert.Equal(t, yen("0"), price)
+
+	_, ok = express.Quote(NewParcel(medium, yen("5000"), exchange.NewConverter("JPY", nil, money.RoundHalfUp)))
+	assert.False(t, ok, "a price without exchange rate is not quoted")
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, raw := range []string{
+		`not json`,
+		`[{"method": "a", "kind": "teleport"}]`,
+		`[{"method": "a", "kind": "weight_tier"}]`,
+		`[{"kind": "flat_rate", "price": "1.00"}]`,
+		`[{"method": "a", "kind": "flat_rate"}, {"method": "a", "kind": "flat_rate"}]`,
+	} {
+		_, err := Parse(raw)
+		assert.Error(t, err, raw)
+	}
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   Config
+		valid bool
+	}{
+		{"None", Config{Exporter: ExporterNone, SampleRatio: 1}, true},
+		{"Stdout", Config{Exporter: ExporterStdout, SampleRatio: 0.5}, true},
+		{"OTLP", Config{Exporter: ExporterOTLP, OTLPEndpoint: "collector:4318", SampleRatio: 1}, true},
+		{"Unknown Exporter", Config{Exporter: "jaeger", SampleRatio: 1}, false},
+		{"Ratio Out Of Range", Config{Exporter: ExporterStdout, SampleRatio: 1.5}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestStdoutExporter(t *testing.T) {
+	var out bytes.Buffer
+	exporter, err := newExporter(context.Background(), Config{Exporter: ExporterStdout}, &out)
+	require.NoError(t, err)
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	_, span := provider.Tracer(ServiceName).Start(context.Background(), "CartService.GetPrice")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	assert.Contains(t, out.String(), `"Name":"CartService.GetPrice"`)
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, span := provider.Tracer(ServiceName).Start(context.Background(), "failing")
+	End(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "boom", spans[0].Status().Description)
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterStdout, SampleRatio: 1})
+	require.NoError(t, err)
+
+	assert.NoError(t, shutdown(context.Background()))
+}
@@ -1,15 +1,67 @@
 package dto
 
+import (
+	"cart-api/pkg/money"
+	"encoding/json"
+	"time"
+)
+
 type AddItemRequest struct {
-	Product string  `json:"product"`
-	Price   float64 `json:"price"`
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type ProductRequest struct {
+	SKU         string      `json:"sku"`
+	Name        string      `json:"name"`
+	Price       money.Money `json:"price"`
+	Currency    string      `json:"currency"`
+	TaxCategory string      `json:"tax_category"`
+	WeightGrams int         `json:"weight_grams"`
+	LengthMM    int         `json:"length_mm"`
+	WidthMM     int         `json:"width_mm"`
+	HeightMM    int         `json:"height_mm"`
+	Active      *bool       `json:"active"`
+}
+
+type ProductResponse struct {
+	SKU         string      `json:"sku"`
+	Name        string      `json:"name"`
+	Price       money.Money `json:"price"`
+	Currency    string      `json:"currency"`
+	TaxCategory string      `json:"tax_category"`
+	WeightGrams int         `json:"weight_grams"`
+	LengthMM    int         `json:"length_mm"`
+	WidthMM     int         `json:"width_mm"`
+	HeightMM    int         `json:"height_mm"`
+	Active      bool        `json:"active"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type StockRequest struct {
+	OnHand int `json:"on_hand"`
+}
+
+type StockResponse struct {
+	SKU       string `json:"sku"`
+	OnHand    int    `json:"on_hand"`
+	Reserved  int    `json:"reserved"`
+	Available int    `json:"available"`
+}
+
+type UpdateItemRequest struct {
+	Quantity int `json:"quantity"`
 }
 
 type ItemResponse struct {
-	ID      int     `json:"id"`
-	CartID  int     `json:"cart_id"`
-	Product string  `json:"product"`
-	Price   float64 `json:"price"`
+	ID       int         `json:"id"`
+	CartID   int         `json:"cart_id"`
+	SKU      string      `json:"sku,omitempty"`
+	Product  string      `json:"product"`
+	Price    money.Money `json:"price"`
+	Currency string      `json:"currency"`
+	Quantity int         `json:"quantity"`
 }
 
 type DeleteItemRequest struct {
@@ -18,13 +70,115 @@ type DeleteItemRequest struct {
 }
 
 type CartResponse struct {
-	ID    int            `json:"id"`
-	Items []ItemResponse `json:"items"`
+	ID       int            `json:"id"`
+	Currency string         `json:"currency,omitempty"`
+	Items    []ItemResponse `json:"items"`
+}
+
+type MergeCartRequest struct {
+	GuestCartID int `json:"guest_cart_id"`
+}
+
+type MergeConflictResponse struct {
+	Product  string `json:"product"`
+	Quantity int    `json:"quantity"`
+	Reason   string `json:"reason"`
+}
+
+type MergeCartResponse struct {
+	Cart      CartResponse            `json:"cart"`
+	Conflicts []MergeConflictResponse `json:"conflicts"`
 }
 
 type PriceResponse struct {
-	CartID          int     `json:"cart_id"`
-	TotalPrice      float64 `json:"total_price"`
-	DiscountPercent int     `json:"discount_percent"`
-	FinalPrice      float64 `json:"final_price"`
+	CartID          int                       `json:"cart_id"`
+	Currency        string                    `json:"currency"`
+	TotalPrice      money.Money               `json:"total_price"`
+	DiscountPercent int                       `json:"discount_percent"`
+	DiscountAmount  money.Money               `json:"discount_amount"`
+	Discounts       []AppliedDiscountResponse `json:"discounts"`
+	Coupons         []AppliedCouponResponse   `json:"coupons"`
+	Shipping        *AppliedShippingResponse  `json:"shipping,omitempty"`
+	FinalPrice      money.Money               `json:"final_price"`
+	Region          string                    `json:"region,omitempty"`
+	TaxMode         string                    `json:"tax_mode,omitempty"`
+	Subtotal        money.Money               `json:"subtotal"`
+	Taxes           []TaxLineResponse         `json:"taxes"`
+	TaxTotal        money.Money               `json:"tax_total"`
+	GrandTotal      money.Money               `json:"grand_total"`
+	Exchange        *ExchangeRateResponse     `json:"exchange,omitempty"`
+}
+
+type ExchangeRateResponse struct {
+	From      string      `json:"from"`
+	To        string      `json:"to"`
+	Rate      json.Number `json:"rate"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type TaxLineResponse struct {
+	Category string      `json:"category"`
+	Rate     json.Number `json:"rate"`
+	Taxable  money.Money `json:"taxable"`
+	Amount   money.Money `json:"amount"`
+}
+
+type AppliedDiscountResponse struct {
+	Rule   string      `json:"rule"`
+	Amount money.Money `json:"amount"`
+}
+
+type AppliedShippingResponse struct {
+	Method string      `json:"method"`
+	Amount money.Money `json:"amount"`
+}
+
+type ShippingOptionResponse struct {
+	Method   string      `json:"method"`
+	Name     string      `json:"name"`
+	Price    money.Money `json:"price"`
+	Currency string      `json:"currency"`
+	Selected bool        `json:"selected"`
+}
+
+type SelectShippingRequest struct {
+	Method string `json:"method"`
+}
+
+type AppliedCouponResponse struct {
+	Code   string      `json:"code"`
+	Amount money.Money `json:"amount"`
+}
+
+type OrderResponse struct {
+	ID        int            `json:"id"`
+	CartID    int            `json:"cart_id"`
+	Items     []ItemResponse `json:"items"`
+	Price     PriceResponse  `json:"price"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type ApplyCouponRequest struct {
+	Code string `json:"code"`
+}
+
+type CouponResponse struct {
+	CartID    int         `json:"cart_id"`
+	Code      string      `json:"code"`
+	Percent   int         `json:"percent,omitempty"`
+	Amount    money.Money `json:"amount"`
+	MinTotal  money.Money `json:"min_total"`
+	Currency  string      `json:"currency"`
+	Stackable bool        `json:"stackable"`
+}
+
+type HealthResponse struct {
+	Status string                         `json:"status"`
+	Checks map[string]HealthCheckResponse `json:"checks,omitempty"`
+}
+
+type HealthCheckResponse struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
 }
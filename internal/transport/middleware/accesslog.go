@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"cart-api/internal/transport/requestid"
+	"cart-api/pkg/logger"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// Router resolves the route pattern a request is served by; *http.ServeMux
+// implements it.
+type Router interface {
+	Handler(*http.Request) (http.Handler, string)
+}
+
+// AccessLog stores a logger tagged with the request and trace ids in the
+// request context and logs one line per request once it has been served.
+func AccessLog(base *zap.Logger, routes Router) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			log := base
+			if id := requestid.FromContext(r.Context()); id != "" {
+				log = log.With(zap.String("request_id", id))
+			}
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				log = log.With(zap.String("trace_id", sc.TraceID().String()))
+			}
+			route := routePattern(routes, r)
+			sw := &statusWriter{ResponseWriter: w}
+			defer func() {
+				log.Info("http request",
+					zap.String("method", r.Method),
+					zap.String("route", route),
+					zap.String("path", r.URL.Path),
+					zap.Int("status", sw.Status()),
+					zap.Int("bytes", sw.bytes),
+					zap.Duration("latency", time.Since(start)),
+				)
+			}()
+			next.ServeHTTP(sw, r.WithContext(logger.WithContext(r.Context(), log)))
+		})
+	}
+}
+
+// routePattern keeps metric and log labels bounded: requests no route matches
+// share a single label instead of their raw path.
+func routePattern(routes Router, r *http.Request) string {
+	if routes != nil {
+		if _, pattern := routes.Handler(r); pattern != "" {
+			return pattern
+		}
+	}
+	return "unmatched"
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Status is the status sent to the client; a handler that wrote nothing
+// answered 200.
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
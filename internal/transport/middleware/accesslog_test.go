@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"cart-api/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLog(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /carts/{cart_id}", func(w http.ResponseWriter, r *http.Request) {
+		logger.FromContext(r.Context(), zap.NewNop()).Info("inside handler")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("short and stout"))
+	})
+	handler := Chain(mux, RequestID(), AccessLog(zap.New(core), mux))
+
+	req := httptest.NewRequest(http.MethodGet, "/carts/7", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "inside handler", entries[0].Message)
+	assert.Equal(t, "req-42", entries[0].ContextMap()["request_id"])
+
+	fields := entries[1].ContextMap()
+	assert.Equal(t, "http request", entries[1].Message)
+	assert.Equal(t, "req-42", fields["request_id"])
+	assert.Equal(t, "GET", fields["method"])
+	assert.Equal(t, "GET /carts/{cart_id}", fields["route"])
+	assert.Equal(t, "/carts/7", fields["path"])
+	assert.EqualValues(t, http.StatusTeapot, fields["status"])
+	assert.EqualValues(t, len("short and stout"), fields["bytes"])
+	assert.Contains(t, fields, "latency")
+}
+
+func TestChain_Order(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	handler := Chain(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		order = append(order, "handler")
+	}), mark("outer"), mark("inner"))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []string{"outer", "inner", "handler"}, order)
+}
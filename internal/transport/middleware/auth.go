@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"cart-api/internal/auth"
+	"cart-api/internal/transport/problem"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+type Verifier interface {
+	Verify(string) (auth.Principal, error)
+}
+
+func Authenticate(verifier Verifier, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				token = ""
+			}
+			principal, err := verifier.Verify(strings.TrimSpace(token))
+			if err != nil {
+				logger.Warn("authentication failed",
+					zap.Error(err),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+				)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="cart-api"`)
+				problem.Error(w, r, http.StatusUnauthorized, problem.CodeUnauthenticated, "a valid bearer token is required")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"cart-api/internal/auth"
+	"cart-api/internal/transport/problem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+type stubVerifier map[string]auth.Principal
+
+func (v stubVerifier) Verify(token string) (auth.Principal, error) {
+	p, ok := v[token]
+	if !ok {
+		return auth.Principal{}, errors.New("bad token")
+	}
+	return p, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	verifier := stubVerifier{"good": {UserID: "user-1"}}
+	var got auth.Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = auth.FromContext(r.Context())
+	})
+	handler := Authenticate(verifier, zaptest.NewLogger(t))(next)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected int
+	}{
+		{name: "Valid", header: "Bearer good", expected: http.StatusOK},
+		{name: "Missing", expected: http.StatusUnauthorized},
+		{name: "Wrong Scheme", header: "Basic good", expected: http.StatusUnauthorized},
+		{name: "Invalid", header: "Bearer bad", expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = auth.Principal{}
+			req := httptest.NewRequest(http.MethodGet, "/carts/1", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusOK {
+				assert.Equal(t, "user-1", got.UserID)
+			} else {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+				assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
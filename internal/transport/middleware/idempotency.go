@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"cart-api/internal/auth"
+	"cart-api/internal/model"
+	"cart-api/internal/transport/problem"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+type IdempotencyStore interface {
+	Begin(context.Context, model.IdempotencyRecord) (*model.IdempotencyRecord, bool, error)
+	Complete(context.Context, model.IdempotencyRecord) error
+	Release(context.Context, model.IdempotencyRecord) error
+}
+
+// Idempotency stores the first response for each Idempotency-Key and replays
+// it for retries of the same request. Keys are scoped to the caller and the
+// request path; server errors are not stored so the request can be retried.
+func Idempotency(store IdempotencyStore, ttl time.Duration, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				problem.Error(w, r, http.StatusBadRequest, problem.CodeValidation, "Idempotency-Key is too long")
+				return
+			}
+			// Read one byte past the limit: hashing a truncated body would let
+			// two different large requests share a stored response.
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentRequestBytes+1))
+			if err != nil {
+				logger.Error("failed to read request body", zap.Error(err))
+				problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "failed to read request body")
+				return
+			}
+			if len(body) > maxIdempotentRequestBytes {
+				problem.Error(w, r, http.StatusRequestEntityTooLarge, problem.CodeBodyTooLarge,
+					fmt.Sprintf("request bodies with an Idempotency-Key must not exceed %d bytes", maxIdempotentRequestBytes))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			hash := sha256.Sum256(body)
+			rec := model.IdempotencyRecord{
+				Key:         key,
+				Route:       r.Method + " " + r.URL.Path,
+				Owner:       idempotencyOwner(r.Context()),
+				RequestHash: hex.EncodeToString(hash[:]),
+				ExpiresAt:   time.Now().Add(ttl),
+			}
+
+			existing, started, err := store.Begin(r.Context(), rec)
+			if err != nil {
+				logger.Error("failed to reserve idempotency key", zap.Error(err), zap.String("key", key))
+				problem.Error(w, r, http.StatusInternalServerError, problem.CodeInternal, "failed to process idempotency key")
+				return
+			}
+			if !started {
+				switch {
+				case existing.RequestHash != rec.RequestHash:
+					logger.Warn("idempotency key reused with a different request", zap.String("key", key), zap.String("route", rec.Route))
+					problem.Error(w, r, http.StatusUnprocessableEntity, problem.CodeIdempotencyKeyReused, "Idempotency-Key was already used for a different request")
+				case !existing.Completed():
+					problem.Error(w, r, http.StatusConflict, problem.CodeIdempotencyInFlight, "a request with this Idempotency-Key is still being processed")
+				default:
+					if existing.ContentType != "" {
+						w.Header().Set("Content-Type", existing.ContentType)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(existing.StatusCode)
+					_, _ = w.Write(existing.Body)
+				}
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w}
+			ctx := context.WithoutCancel(r.Context())
+			defer func() {
+				if recorder.status == 0 || recorder.status >= http.StatusInternalServerError {
+					if err := store.Release(ctx, rec); err != nil {
+						logger.Error("failed to release idempotency key", zap.Error(err), zap.String("key", key))
+					}
+					return
+				}
+				rec.StatusCode = recorder.status
+				rec.ContentType = recorder.Header().Get("Content-Type")
+				rec.Body = recorder.body.Bytes()
+				if err := store.Complete(ctx, rec); err != nil {
+					logger.Error("failed to store idempotent response", zap.Error(err), zap.String("key", key))
+				}
+			}()
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+func idempotencyOwner(ctx context.Context) string {
+	p, ok := auth.FromContext(ctx)
+	switch {
+	case !ok:
+		return ""
+	case p.UserID != "":
+		return "user:" + p.UserID
+	default:
+		return "session:" + p.SessionID
+	}
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
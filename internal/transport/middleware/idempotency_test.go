@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"cart-api/internal/auth"
+	"cart-api/internal/model"
+	"cart-api/internal/transport/problem"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]model.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: map[string]model.IdempotencyRecord{}}
+}
+
+func (s *memoryIdempotencyStore) id(rec model.IdempotencyRecord) string {
+	return rec.Key + "|" + rec.Route + "|" + rec.Owner
+}
+
+func (s *memoryIdempotencyStore) Begin(_ context.Context, rec model.IdempotencyRecord) (*model.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[s.id(rec)]; ok {
+		return &existing, false, nil
+	}
+	s.records[s.id(rec)] = rec
+	return &rec, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, rec model.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[s.id(rec)] = rec
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, rec model.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, s.id(rec))
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	var calls int
+	status := http.StatusCreated
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"id": %d}`, calls)
+	})
+	store := newMemoryIdempotencyStore()
+	handler := Idempotency(store, time.Hour, zaptest.NewLogger(t))(next)
+
+	send := func(key, path, body string, p auth.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		req = req.WithContext(auth.WithPrincipal(req.Context(), p))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	user := auth.Principal{UserID: "user-1"}
+
+	first := send("key-1", "/carts/1/items", `{"product": "Shoes"}`, user)
+	require.Equal(t, http.StatusCreated, first.Code)
+
+	t.Run("Replay", func(t *testing.T) {
+		w := send("key-1", "/carts/1/items", `{"product": "Shoes"}`, user)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, first.Body.String(), w.Body.String())
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Different Body", func(t *testing.T) {
+		w := send("key-1", "/carts/1/items", `{"product": "Socks"}`, user)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Different Route", func(t *testing.T) {
+		w := send("key-1", "/carts/2/items", `{"product": "Shoes"}`, user)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Different Caller", func(t *testing.T) {
+		w := send("key-1", "/carts/1/items", `{"product": "Shoes"}`, auth.Principal{SessionID: "sess-1"})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("No Key", func(t *testing.T) {
+		send("", "/carts/1/items", `{"product": "Shoes"}`, user)
+		send("", "/carts/1/items", `{"product": "Shoes"}`, user)
+
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("Server Error Is Not Stored", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		send("key-2", "/carts", `{}`, user)
+		status = http.StatusCreated
+		w := send("key-2", "/carts", `{}`, user)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 7, calls)
+	})
+
+	t.Run("In Progress", func(t *testing.T) {
+		_, _, err := store.Begin(context.Background(), model.IdempotencyRecord{
+			Key:         "key-3",
+			Route:       "POST /carts",
+			Owner:       "user:user-1",
+			RequestHash: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+		})
+		require.NoError(t, err)
+
+		w := send("key-3", "/carts", `{}`, user)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 7, calls)
+	})
+
+	t.Run("Body Too Large", func(t *testing.T) {
+		body := `{"product": "` + strings.Repeat("x", maxIdempotentRequestBytes) + `"}`
+		w := send("key-4", "/carts/1/items", body, user)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), problem.CodeBodyTooLarge)
+		assert.Equal(t, 7, calls)
+	})
+}
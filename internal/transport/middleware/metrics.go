@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, d time.Duration)
+}
+
+// Metrics reports every request with its route pattern, status and latency.
+func Metrics(observer RequestObserver, routes Router) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			route := routePattern(routes, r)
+			sw := &statusWriter{ResponseWriter: w}
+			defer func() {
+				observer.ObserveRequest(r.Method, route, sw.Status(), time.Since(start))
+			}()
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
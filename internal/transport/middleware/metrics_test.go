@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type observedRequest struct {
+	method, route string
+	status        int
+}
+
+type recordingObserver []observedRequest
+
+func (o *recordingObserver) ObserveRequest(method, route string, status int, _ time.Duration) {
+	*o = append(*o, observedRequest{method, route, status})
+}
+
+func TestMetrics(t *testing.T) {
+	observer := &recordingObserver{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /carts/{cart_id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := Metrics(observer, mux)(mux)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/carts/5", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope/5", nil))
+
+	assert.Equal(t, recordingObserver{
+		{http.MethodDelete, "DELETE /carts/{cart_id}", http.StatusNoContent},
+		{http.MethodGet, "unmatched", http.StatusNotFound},
+	}, *observer)
+}
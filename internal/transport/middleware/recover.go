@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"cart-api/internal/transport/problem"
+	"cart-api/pkg/logger"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// Recover turns a handler panic into a logged 500 instead of a dropped
+// connection. http.ErrAbortHandler is re-raised so net/http can abort the
+// response as intended.
+func Recover(base *zap.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := &statusWriter{ResponseWriter: w}
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				logger.FromContext(r.Context(), base).Error("handler panicked",
+					zap.String("panic", fmt.Sprint(rec)),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Stack("stack"),
+				)
+				if sw.status == 0 {
+					problem.Error(sw, r, http.StatusInternalServerError, problem.CodeInternal, "The request could not be processed")
+				}
+			}()
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
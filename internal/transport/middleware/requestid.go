@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"cart-api/internal/transport/requestid"
+	"net/http"
+)
+
+// RequestID propagates the caller's X-Request-ID or generates a new one, and
+// echoes it in the response.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestid.Header)
+			if !requestid.Valid(id) {
+				id = requestid.New()
+				r.Header.Set(requestid.Header, id)
+			}
+			w.Header().Set(requestid.Header, id)
+			next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"cart-api/internal/transport/requestid"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var got string
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = requestid.FromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		header   string
+		generate bool
+	}{
+		{name: "Propagated", header: "abc-123"},
+		{name: "Missing", generate: true},
+		{name: "Unsafe Characters", header: "abc\r\nX-Injected: 1", generate: true},
+		{name: "Too Long", header: strings.Repeat("a", requestid.MaxLength+1), generate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/carts/1", nil)
+			if tt.header != "" {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if tt.generate {
+				assert.Len(t, got, 32)
+				assert.NotEqual(t, tt.header, got)
+			} else {
+				assert.Equal(t, tt.header, got)
+			}
+			assert.Equal(t, got, w.Header().Get(requestid.Header))
+		})
+	}
+}
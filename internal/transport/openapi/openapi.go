@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// Spec is the OpenAPI 3 document describing every route registered by
+// app.Run. It is maintained by hand; the drift tests keep it in sync with the
+// routes and the dto package.
+//
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed docs.html
+var docsPage []byte
+
+// Handler serves Spec as JSON.
+func Handler() http.Handler {
+	return static("application/json", Spec)
+}
+
+// DocsHandler serves a self-contained page that renders Spec and lets callers
+// try the operations from the browser.
+func DocsHandler() http.Handler {
+	return static("text/html; charset=utf-8", docsPage)
+}
+
+func static(contentType string, body []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		_, _ = w.Write(body)
+	})
+}
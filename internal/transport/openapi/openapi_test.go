@@ -0,0 +1,285 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemasWithoutDTO are component schemas that intentionally have no
+// counterpart in the dto package.
+var schemasWithoutDTO = map[string]bool{
+	"Money":   true,
+	"Problem": true,
+}
+
+func loadSpec(t *testing.T) map[string]any {
+	t.Helper()
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(Spec, &doc))
+	return doc
+}
+
+func TestSpec_Valid(t *testing.T) {
+	doc := loadSpec(t)
+	assert.True(t, strings.HasPrefix(doc["openapi"].(string), "3."), "openapi version")
+	require.NotEmpty(t, doc["paths"])
+
+	var walk func(path string, node any)
+	walk = func(path string, node any) {
+		switch v := node.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				_, err := resolveRef(doc, ref)
+				assert.NoError(t, err, path)
+			}
+			for key, child := range v {
+				walk(path+"/"+key, child)
+			}
+		case []any:
+			for i, child := range v {
+				walk(fmt.Sprintf("%s/%d", path, i), child)
+			}
+		}
+	}
+	walk("#", doc)
+
+	operationIDs := map[string]string{}
+	for path, item := range doc["paths"].(map[string]any) {
+		for method, op := range item.(map[string]any) {
+			id, _ := op.(map[string]any)["operationId"].(string)
+			require.NotEmpty(t, id, "%s %s has no operationId", method, path)
+			if other, dup := operationIDs[id]; dup {
+				t.Errorf("operationId %q used by %s and %s %s", id, other, method, path)
+			}
+			operationIDs[id] = method + " " + path
+		}
+	}
+}
+
+// TestSpec_MatchesDTOs fails when a type of the dto package is added, removed
+// or changes its JSON fields without the spec following.
+func TestSpec_MatchesDTOs(t *testing.T) {
+	structs := parseDTOs(t)
+	schemas := loadSpec(t)["components"].(map[string]any)["schemas"].(map[string]any)
+
+	for name, fields := range structs {
+		t.Run(name, func(t *testing.T) {
+			raw, ok := schemas[name]
+			require.True(t, ok, "dto.%s has no schema in openapi.json", name)
+			props, _ := raw.(map[string]any)["properties"].(map[string]any)
+
+			assert.ElementsMatch(t, sortedKeys(fields), sortedKeys(props), "JSON fields of dto.%s", name)
+			for field, expr := range fields {
+				prop, ok := props[field]
+				if !ok {
+					continue
+				}
+				assert.NoError(t, matchesSchema(expr, prop.(map[string]any)), "dto.%s.%s", name, field)
+			}
+		})
+	}
+
+	for name := range schemas {
+		if _, ok := structs[name]; !ok && !schemasWithoutDTO[name] {
+			t.Errorf("schema %s has no dto type", name)
+		}
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.Handler
+		contentType string
+		contains    string
+	}{
+		{"spec", Handler(), "application/json", `"openapi": "3.`},
+		{"docs", DocsHandler(), "text/html; charset=utf-8", `fetch("openapi.json")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Body.String(), tt.contains)
+		})
+	}
+}
+
+// parseDTOs returns the JSON field names and Go types of every struct
+// declared in the dto package.
+func parseDTOs(t *testing.T) map[string]map[string]ast.Expr {
+	t.Helper()
+	paths, err := filepath.Glob("../dto/*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	structs := map[string]map[string]ast.Expr{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		require.NoError(t, err)
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			fields := map[string]ast.Expr{}
+			for _, field := range st.Fields.List {
+				name := jsonName(field)
+				if name != "" {
+					fields[name] = field.Type
+				}
+			}
+			structs[spec.Name.Name] = fields
+			return false
+		})
+	}
+	require.NotEmpty(t, structs)
+	return structs
+}
+
+func jsonName(field *ast.Field) string {
+	if field.Tag == nil || len(field.Names) == 0 || !field.Names[0].IsExported() {
+		return ""
+	}
+	tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+	name, _, _ := strings.Cut(tag, ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Names[0].Name
+	}
+	return name
+}
+
+// matchesSchema checks that a Go field type serializes to what the schema
+// declares. Named dto types must be referenced, not inlined.
+func matchesSchema(expr ast.Expr, schema map[string]any) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		want := refFor(expr)
+		if want == "" || !strings.HasSuffix(ref, "/"+want) {
+			return fmt.Errorf("schema references %s, Go type is %s", ref, typeString(expr))
+		}
+		return nil
+	}
+	typ, _ := schema["type"].(string)
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return matchesSchema(e.X, schema)
+	case *ast.ArrayType:
+		if typ != "array" {
+			return fmt.Errorf("schema type %q, Go type is %s", typ, typeString(expr))
+		}
+		items, _ := schema["items"].(map[string]any)
+		return matchesSchema(e.Elt, items)
+	case *ast.MapType:
+		if typ != "object" {
+			return fmt.Errorf("schema type %q, Go type is %s", typ, typeString(expr))
+		}
+		values, _ := schema["additionalProperties"].(map[string]any)
+		return matchesSchema(e.Value, values)
+	}
+	want := scalarType(expr)
+	if want == "" {
+		return fmt.Errorf("Go type %s must be a $ref", typeString(expr))
+	}
+	if typ != want {
+		return fmt.Errorf("schema type %q, Go type %s serializes as %q", typ, typeString(expr), want)
+	}
+	return nil
+}
+
+func scalarType(expr ast.Expr) string {
+	switch typeString(expr) {
+	case "string", "time.Time":
+		return "string"
+	case "int", "int64":
+		return "integer"
+	case "json.Number":
+		return "number"
+	case "bool":
+		return "boolean"
+	}
+	return ""
+}
+
+func refFor(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return refFor(e.X)
+	case *ast.Ident:
+		if scalarType(e) == "" {
+			return e.Name
+		}
+	case *ast.SelectorExpr:
+		if typeString(e) == "money.Money" {
+			return "Money"
+		}
+	}
+	return ""
+}
+
+func typeString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return typeString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + typeString(e.X)
+	case *ast.ArrayType:
+		return "[]" + typeString(e.Elt)
+	case *ast.MapType:
+		return "map[" + typeString(e.Key) + "]" + typeString(e.Value)
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+func resolveRef(doc map[string]any, ref string) (any, error) {
+	path, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("only local references are supported: %s", ref)
+	}
+	var node any = doc
+	for _, key := range strings.Split(path, "/") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolved reference %s", ref)
+		}
+		if node, ok = m[key]; !ok {
+			return nil, fmt.Errorf("unresolved reference %s", ref)
+		}
+	}
+	return node, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package problem
+
+import "net/http"
+
+// Codes are part of the API contract: once published they must not change.
+const (
+	CodeInvalidCartID        = "invalid_cart_id"
+	CodeInvalidItemID        = "invalid_item_id"
+	CodeInvalidBody          = "invalid_body"
+	CodeValidation           = "validation_failed"
+	CodeUnauthenticated      = "unauthenticated"
+	CodeForbidden            = "forbidden"
+	CodeAdminRequired        = "admin_required"
+	CodeCartNotFound         = "cart_not_found"
+	CodeItemNotFound         = "item_not_found"
+	CodeProductNotFound      = "product_not_found"
+	CodeProductExists        = "product_exists"
+	CodeCouponNotFound       = "coupon_not_found"
+	CodeCouponConflict       = "coupon_conflict"
+	CodeCouponRejected       = "coupon_rejected"
+	CodeCartLimit            = "cart_limit_exceeded"
+	CodeInsufficientStock    = "insufficient_stock"
+	CodeCartNotOpen          = "cart_not_open"
+	CodeCartEmpty            = "cart_empty"
+	CodeCheckoutNotStarted   = "checkout_not_started"
+	CodeMergeRejected        = "merge_rejected"
+	CodeVersionMismatch      = "version_mismatch"
+	CodeCurrencyMismatch     = "currency_mismatch"
+	CodeUnknownExchangeRate  = "unknown_exchange_rate"
+	CodeUnknownTaxRegion     = "unknown_tax_region"
+	CodeShippingUnavailable  = "shipping_unavailable"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeIdempotencyInFlight  = "idempotency_in_progress"
+	CodeBodyTooLarge         = "body_too_large"
+	CodeInternal             = "internal_error"
+)
+
+var titles = map[string]string{
+	CodeInvalidCartID:        "Invalid cart ID",
+	CodeInvalidItemID:        "Invalid item ID",
+	CodeInvalidBody:          "Malformed request body",
+	CodeValidation:           "Validation failed",
+	CodeUnauthenticated:      "Authentication required",
+	CodeForbidden:            "Access to the cart is forbidden",
+	CodeAdminRequired:        "Administrator access required",
+	CodeCartNotFound:         "Cart not found",
+	CodeItemNotFound:         "Item not found",
+	CodeProductNotFound:      "Product not found",
+	CodeProductExists:        "Product already exists",
+	CodeCouponNotFound:       "Coupon not found",
+	CodeCouponConflict:       "Coupon conflict",
+	CodeCouponRejected:       "Coupon rejected",
+	CodeCartLimit:            "Cart limit exceeded",
+	CodeInsufficientStock:    "Insufficient stock",
+	CodeCartNotOpen:          "Cart is not open",
+	CodeCartEmpty:            "Cart is empty",
+	CodeCheckoutNotStarted:   "Checkout not started",
+	CodeMergeRejected:        "Merge rejected",
+	CodeVersionMismatch:      "Cart version mismatch",
+	CodeCurrencyMismatch:     "Currency mismatch",
+	CodeUnknownExchangeRate:  "Unknown exchange rate",
+	CodeUnknownTaxRegion:     "Unknown tax region",
+	CodeShippingUnavailable:  "Shipping method unavailable",
+	CodeIdempotencyKeyReused: "Idempotency key reused",
+	CodeIdempotencyInFlight:  "Request still in progress",
+	CodeBodyTooLarge:         "Request body too large",
+	CodeInternal:             "Internal server error",
+}
+
+// Title is the human-readable summary of a code, falling back to the status
+// text for codes without one.
+func Title(code string, status int) string {
+	if title, ok := titles[code]; ok {
+		return title
+	}
+	return http.StatusText(status)
+}
@@ -0,0 +1,104 @@
+package problem
+
+import (
+	"cart-api/internal/repository/Cart"
+	"cart-api/internal/repository/Coupon"
+	"cart-api/internal/repository/Product"
+	"cart-api/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type errorMapping struct {
+	status int
+	code   string
+	errs   []error
+}
+
+// errorMappings is shared by the REST and gRPC transports through For, so
+// both classify a service or repository error the same way.
+var errorMappings = []errorMapping{
+	{http.StatusUnauthorized, CodeUnauthenticated, []error{services.ErrUnauthenticated}},
+	{http.StatusForbidden, CodeForbidden, []error{services.ErrForbidden}},
+	{http.StatusForbidden, CodeAdminRequired, []error{services.ErrAdminRequired}},
+	{http.StatusNotFound, CodeCartNotFound, []error{services.ErrCartNotFound}},
+	{http.StatusNotFound, CodeItemNotFound, []error{services.ErrItemNotFound, Cart.ErrNotFound}},
+	{http.StatusNotFound, CodeProductNotFound, []error{services.ErrProductNotFound, Product.ErrNotFound}},
+	{http.StatusConflict, CodeProductExists, []error{Product.ErrAlreadyExists}},
+	{http.StatusNotFound, CodeCouponNotFound, []error{
+		services.ErrCouponNotFound, Coupon.ErrNotFound, Coupon.ErrNotApplied,
+	}},
+	{http.StatusConflict, CodeCouponConflict, []error{
+		services.ErrCouponAlreadyApplied, Coupon.ErrAlreadyApplied, services.ErrCouponNotStackable,
+	}},
+	{http.StatusBadRequest, CodeCouponRejected, []error{
+		services.ErrCouponNotActive, services.ErrCouponExpired, services.ErrCouponUsageLimit,
+		Coupon.ErrUsageLimitReached, services.ErrCouponMinTotal, services.ErrCouponCurrency,
+	}},
+	{http.StatusPreconditionFailed, CodeVersionMismatch, []error{services.ErrVersionMismatch}},
+	{http.StatusConflict, CodeCartNotOpen, []error{services.ErrCartNotOpen, services.ErrInvalidTransition}},
+	{http.StatusConflict, CodeCheckoutNotStarted, []error{services.ErrCheckoutNotStarted}},
+	{http.StatusConflict, CodeCurrencyMismatch, []error{services.ErrCurrencyMismatch}},
+	{http.StatusBadRequest, CodeCartEmpty, []error{services.ErrCartEmpty}},
+	{http.StatusBadRequest, CodeMergeRejected, []error{
+		services.ErrMergeSameCart, services.ErrMergeNotGuest, services.ErrMergeTargetGuest,
+	}},
+	{http.StatusBadRequest, CodeCartLimit, []error{services.ErrReachCartLimit}},
+	{http.StatusBadRequest, CodeUnknownExchangeRate, []error{services.ErrUnknownExchangeRate}},
+	{http.StatusBadRequest, CodeUnknownTaxRegion, []error{services.ErrUnknownTaxRegion}},
+	{http.StatusBadRequest, CodeShippingUnavailable, []error{
+		services.ErrUnknownShippingMethod, services.ErrShippingUnavailable,
+	}},
+	{http.StatusBadRequest, CodeValidation, []error{
+		services.ErrInvalidProduct, services.ErrUnknownProduct, services.ErrProductInactive,
+		services.ErrInvalidPrice, services.ErrInvalidQuantity, services.ErrInvalidCurrency,
+		services.ErrInvalidSKU, services.ErrInvalidTaxCategory, services.ErrInvalidDimensions,
+	}},
+}
+
+// For translates a service or repository error into a  Errors
+// without a mapping become a 500 whose detail does not leak the cause.
+func For(err error) Problem {
+	var stockErr *services.InsufficientStockError
+	if errors.As(err, &stockErr) {
+		return New(http.StatusConflict, CodeInsufficientStock, stockErr.Error()).
+			With("sku", stockErr.SKU).
+			With("requested", stockErr.Requested).
+			With("available", stockErr.Available)
+	}
+	var limitErr *services.LimitError
+	if errors.As(err, &limitErr) {
+		code := CodeCartLimit
+		if !errors.Is(limitErr, services.ErrReachCartLimit) {
+			code = CodeValidation
+		}
+		p := New(http.StatusBadRequest, code, limitErr.Error()).With("limit", limitErr.Limit)
+		if limitErr.Max != "" {
+			p = p.With("max", json.Number(limitErr.Max))
+		}
+		if limitErr.Actual != "" {
+			p = p.With("actual", json.Number(limitErr.Actual))
+		}
+		if limitErr.Pattern != "" {
+			p = p.With("pattern", limitErr.Pattern)
+		}
+		return p
+	}
+	var cartNotFound *Cart.ErrCartNotFound
+	if errors.As(err, &cartNotFound) {
+		return New(http.StatusNotFound, CodeCartNotFound, cartNotFound.Error())
+	}
+	var itemNotFound *Cart.ErrCartItemNotFound
+	if errors.As(err, &itemNotFound) {
+		return New(http.StatusNotFound, CodeItemNotFound, itemNotFound.Error())
+	}
+	for _, m := range errorMappings {
+		for _, target := range m.errs {
+			if errors.Is(err, target) {
+				return New(m.status, m.code, err.Error())
+			}
+		}
+	}
+	return New(http.StatusInternalServerError, CodeInternal, "The request could not be processed")
+}
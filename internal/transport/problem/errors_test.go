@@ -0,0 +1,58 @@
+package problem
+
+import (
+	"cart-api/internal/repository/Cart"
+	"cart-api/internal/repository/Coupon"
+	"cart-api/internal/repository/Product"
+	"cart-api/internal/services"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"Wrapped Cart Not Found", fmt.Errorf("lock: %w", services.ErrCartNotFound), http.StatusNotFound, CodeCartNotFound},
+		{"Repository Cart Not Found", &Cart.ErrCartNotFound{ID: 3}, http.StatusNotFound, CodeCartNotFound},
+		{"Item Not Found", Cart.ErrNotFound, http.StatusNotFound, CodeItemNotFound},
+		{"Product Exists", Product.ErrAlreadyExists, http.StatusConflict, CodeProductExists},
+		{"Coupon Not Applied", Coupon.ErrNotApplied, http.StatusNotFound, CodeCouponNotFound},
+		{"Coupon Expired", services.ErrCouponExpired, http.StatusBadRequest, CodeCouponRejected},
+		{"Version Mismatch", services.ErrVersionMismatch, http.StatusPreconditionFailed, CodeVersionMismatch},
+		{"Forbidden", services.ErrForbidden, http.StatusForbidden, CodeForbidden},
+		{"Name Limit", &services.LimitError{Limit: services.LimitNameLength, Max: "10"}, http.StatusBadRequest, CodeValidation},
+		{"Unknown", errors.New("connection reset"), http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := For(tt.err)
+
+			assert.Equal(t, tt.status, p.Status)
+			assert.Equal(t, tt.code, p.Code)
+			assert.Equal(t, TypePrefix+tt.code, p.Type)
+		})
+	}
+
+	t.Run("Internal Detail Hides Cause", func(t *testing.T) {
+		p := For(errors.New("pq: password authentication failed"))
+
+		assert.NotContains(t, p.Detail, "password")
+	})
+
+	t.Run("Stock Extensions", func(t *testing.T) {
+		body, err := json.Marshal(For(&services.InsufficientStockError{SKU: "APL-1", Requested: 3, Available: 2}))
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `"available":2,"requested":3,"sku":"APL-1"`)
+	})
+}
@@ -0,0 +1,108 @@
+package problem
+
+import (
+	"bytes"
+	"cart-api/internal/transport/requestid"
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+const ContentType = "application/problem+json"
+
+// TypePrefix is prepended to the code to build the problem type URI.
+const TypePrefix = "/problems/"
+
+// Problem is an RFC 7807 problem details object. Code is a stable,
+// machine-readable identifier clients can switch on; Extensions are
+// serialized as additional top-level members.
+type Problem struct {
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Status     int            `json:"status"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Code       string         `json:"code"`
+	RequestID  string         `json:"request_id,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+func New(status int, code, detail string) Problem {
+	return Problem{
+		Type:   TypePrefix + code,
+		Title:  Title(code, status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// With returns a copy of p with an extension member set.
+func (p Problem) With(key string, value any) Problem {
+	ext := make(map[string]any, len(p.Extensions)+1)
+	for k, v := range p.Extensions {
+		ext[k] = v
+	}
+	ext[key] = value
+	p.Extensions = ext
+	return p
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type members Problem
+	body, err := json.Marshal(members(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return body, err
+	}
+	keys := make([]string, 0, len(p.Extensions))
+	for key := range p.Extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.Write(body[:len(body)-1])
+	for _, key := range keys {
+		if reserved(key) {
+			continue
+		}
+		value, err := json.Marshal(p.Extensions[key])
+		if err != nil {
+			return nil, err
+		}
+		name, _ := json.Marshal(key)
+		buf.WriteByte(',')
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func reserved(key string) bool {
+	switch key {
+	case "type", "title", "status", "detail", "instance", "code", "request_id":
+		return true
+	}
+	return false
+}
+
+// Write sends p as application/problem+json, filling in the request path and
+// request id.
+func Write(w http.ResponseWriter, r *http.Request, p Problem) {
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = requestid.FromRequest(r)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// Error writes a problem without extension members.
+func Error(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	Write(w, r, New(status, code, detail))
+}
@@ -0,0 +1,65 @@
+package problem
+
+import (
+	"cart-api/internal/transport/requestid"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblem_MarshalJSON(t *testing.T) {
+	p := New(http.StatusConflict, CodeInsufficientStock, "not enough apples").
+		With("available", 2).
+		With("sku", "APL-1").
+		With("status", 500)
+
+	body, err := json.Marshal(p)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "/problems/insufficient_stock",
+		"title": "Insufficient stock",
+		"status": 409,
+		"detail": "not enough apples",
+		"code": "insufficient_stock",
+		"available": 2,
+		"sku": "APL-1"
+	}`, string(body))
+}
+
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "Cart not found", Title(CodeCartNotFound, http.StatusNotFound))
+	assert.Equal(t, "Bad Request", Title("unlisted", http.StatusBadRequest))
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("Request ID From Context", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/carts/1", nil)
+		req = req.WithContext(requestid.NewContext(req.Context(), "req-1"))
+		w := httptest.NewRecorder()
+
+		Error(w, req, http.StatusNotFound, CodeCartNotFound, "cart not found")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+		var got Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, "req-1", got.RequestID)
+		assert.Equal(t, "/carts/1", got.Instance)
+		assert.Equal(t, CodeCartNotFound, got.Code)
+	})
+
+	t.Run("Request ID From Header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/carts/1", nil)
+		req.Header.Set(requestid.Header, "client-7")
+		w := httptest.NewRecorder()
+
+		Error(w, req, http.StatusBadRequest, CodeInvalidCartID, "bad id")
+
+		assert.Contains(t, w.Body.String(), `"request_id":"client-7"`)
+	})
+}
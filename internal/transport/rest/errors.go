@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"cart-api/internal/transport/problem"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// writeError logs err and answers with its problem. Client errors are logged
+// as warnings, everything else as errors.
+func writeError(w http.ResponseWriter, r *http.Request, logger *zap.Logger, err error, fields ...zap.Field) {
+	p := problem.For(err)
+	fields = append(fields, zap.Error(err), zap.String("code", p.Code))
+	if p.Status >= http.StatusInternalServerError {
+		logger.Error("request failed", fields...)
+	} else {
+		logger.Warn("request rejected", fields...)
+	}
+	problem.Write(w, r, p)
+}
@@ -1,26 +1,38 @@
 package rest
 
 import (
+	"cart-api/internal/exchange"
 	"cart-api/internal/model"
-	"cart-api/internal/repository/Cart"
+	"cart-api/internal/pricing"
 	"cart-api/internal/services"
 	"cart-api/internal/transport/dto"
+	"cart-api/internal/transport/problem"
+	"cart-api/pkg/logger"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type CartProvider interface {
 	CreateCart(context.Context) (*model.Cart, error)
-	CreateItem(context.Context, model.CartItem) (int, error)
+	CreateItem(context.Context, model.CartItem) (*model.CartItem, error)
+	UpdateItem(context.Context, model.CartItem) (*model.CartItem, error)
 	DeleteItem(context.Context, model.CartItem) error
 	GetCart(context.Context, int) (*model.Cart, error)
 	GetPrice(context.Context, int) (*model.Price, error)
+	ApplyCoupon(context.Context, int, string) (*model.Coupon, error)
+	RemoveCoupon(context.Context, int, string) error
+	MergeCart(context.Context, int, int) (*model.MergeResult, error)
+	BeginCheckout(context.Context, int) (*model.Price, error)
+	Checkout(context.Context, int) (*model.Order, error)
+	CancelCheckout(context.Context, int) error
+	ShippingOptions(context.Context, int) ([]model.ShippingOption, error)
+	SelectShipping(context.Context, int, string) (*model.ShippingOption, error)
 }
 
 type CartHandler struct {
@@ -43,36 +55,28 @@ func (h *CartHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
 	cartItem := r.PathValue("item_id")
 	id, err := strconv.Atoi(cartID)
 	if err != nil {
-		h.logger.Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
-		http.Error(w, fmt.Sprintf("invalid cart ID; '%s'must be an integer", cartID), http.StatusBadRequest)
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
 		return
 	}
 	itemID, err := strconv.Atoi(cartItem)
 	if err != nil {
-		h.logger.Error("failed to parse cart item", zap.Error(err), zap.String("input", cartItem))
-		http.Error(w, fmt.Sprintf("invalid item ID; '%s' must be an integer", cartItem), http.StatusBadRequest)
+		h.log(ctx).Error("failed to parse cart item", zap.Error(err), zap.String("input", cartItem))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidItemID, fmt.Sprintf("invalid item ID; '%s' must be an integer", cartItem))
+		return
+	}
+	ctx, ok := withIfMatch(ctx, r)
+	if !ok {
+		problem.Error(w, r, http.StatusPreconditionFailed, problem.CodeVersionMismatch, "If-Match does not match the current cart version")
 		return
 	}
 	item := model.CartItem{Id: itemID, CartId: id}
 	err = h.service.DeleteItem(ctx, item)
 	if err != nil {
-		if errors.Is(err, Cart.ErrNotFound) {
-			h.logger.Warn("attempt to delete non-existent item",
-				zap.Int("cart_id", id),
-				zap.Int("item_id", itemID),
-			)
-			http.Error(w, fmt.Sprintf("Item %d not found in cart %d", itemID, id), http.StatusNotFound)
-			return
-		}
-		h.logger.Error("failed to delete item",
-			zap.Error(err),
-			zap.Int("cart_id", id),
-			zap.Int("item_id", itemID),
-		)
-		http.Error(w, "Failed to delete item", http.StatusInternalServerError)
+		h.writeError(w, r, err, zap.Int("cart_id", id), zap.Int("item_id", itemID))
 		return
 	}
-	h.logger.Info("item deleted successfully",
+	h.log(ctx).Info("item deleted successfully",
 		zap.Int("cart_id", id),
 		zap.Int("item_id", itemID),
 	)
@@ -85,8 +89,7 @@ func (h *CartHandler) PostCart(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 	cart, err := h.service.CreateCart(ctx)
 	if err != nil {
-		h.logger.Error("error creating cart", zap.Error(err))
-		http.Error(w, "Failed to create new cart", http.StatusInternalServerError)
+		h.writeError(w, r, err)
 		return
 	}
 	resp := dto.CartResponse{
@@ -95,8 +98,7 @@ func (h *CartHandler) PostCart(w http.ResponseWriter, r *http.Request) {
 	}
 	err = json.NewEncoder(w).Encode(resp)
 	if err != nil {
-		h.logger.Error("error encoding cart", zap.Error(err))
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		h.log(ctx).Error("error encoding cart", zap.Error(err))
 		return
 	}
 }
@@ -108,62 +110,75 @@ func (h *CartHandler) PostItem(w http.ResponseWriter, r *http.Request) {
 	cartID := r.PathValue("cart_id")
 	id, err := strconv.Atoi(cartID)
 	if err != nil {
-		h.logger.Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
-		http.Error(w, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID), http.StatusBadRequest)
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
 		return
 	}
 	var req dto.AddItemRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Error("invalid request body", zap.Error(err))
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		h.log(ctx).Error("invalid request body", zap.Error(err))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "request body must be valid JSON")
+		return
+	}
+	ctx, ok := withIfMatch(ctx, r)
+	if !ok {
+		problem.Error(w, r, http.StatusPreconditionFailed, problem.CodeVersionMismatch, "If-Match does not match the current cart version")
 		return
 	}
 	itemModel := model.CartItem{
-		CartId:  id,
-		Product: req.Product,
-		Price:   req.Price,
+		CartId:   id,
+		SKU:      req.SKU,
+		Quantity: req.Quantity,
 	}
-	newID, err := h.service.CreateItem(ctx, itemModel)
+	item, err := h.service.CreateItem(ctx, itemModel)
 	if err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id), zap.String("sku", req.SKU))
+		return
+	}
+	err = json.NewEncoder(w).Encode(toItemResponse(*item))
+	if err != nil {
+		h.log(ctx).Error("error encoding cartItem", zap.Error(err))
+		return
+	}
+}
 
-		if errors.Is(err, services.ErrInvalidProduct) ||
-			errors.Is(err, services.ErrInvalidPrice) ||
-			errors.Is(err, services.ErrReachCartLimit) {
-			h.logger.Warn("business rule violation",
-				zap.Error(err),
-				zap.Int("cart_id", id),
-			)
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		if errors.Is(err, services.ErrCartNotFound) {
-			h.logger.Warn("cart not found",
-				zap.Int("cart_id", id),
-			)
-			http.Error(w, fmt.Sprintf("Cart with id %d not found", id), http.StatusNotFound)
-			return
-		}
-
-		h.logger.Error("failed to create item",
-			zap.Error(err),
-			zap.Int("cart_id", id),
-			zap.String("product", req.Product),
-			zap.Float64("price", req.Price),
-		)
-		http.Error(w, "Internal server error processing item creation", http.StatusInternalServerError)
+func (h *CartHandler) PatchItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	cartItem := r.PathValue("item_id")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
 		return
 	}
-	resp := dto.ItemResponse{
-		ID:      newID,
-		CartID:  id,
-		Product: itemModel.Product,
-		Price:   itemModel.Price,
+	itemID, err := strconv.Atoi(cartItem)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart item", zap.Error(err), zap.String("input", cartItem))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidItemID, fmt.Sprintf("invalid item ID; '%s' must be an integer", cartItem))
+		return
 	}
-	err = json.NewEncoder(w).Encode(resp)
+	var req dto.UpdateItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(ctx).Error("invalid request body", zap.Error(err))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "request body must be valid JSON")
+		return
+	}
+	ctx, ok := withIfMatch(ctx, r)
+	if !ok {
+		problem.Error(w, r, http.StatusPreconditionFailed, problem.CodeVersionMismatch, "If-Match does not match the current cart version")
+		return
+	}
+	item, err := h.service.UpdateItem(ctx, model.CartItem{Id: itemID, CartId: id, Quantity: req.Quantity})
+	if err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id), zap.Int("item_id", itemID))
+		return
+	}
+	err = json.NewEncoder(w).Encode(toItemResponse(*item))
 	if err != nil {
-		h.logger.Error("error encoding cartItem", zap.Error(err))
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		h.log(ctx).Error("error encoding cartItem", zap.Error(err))
 		return
 	}
 }
@@ -175,54 +190,174 @@ func (h *CartHandler) GetItems(w http.ResponseWriter, r *http.Request) {
 	cartID := r.PathValue("cart_id")
 	id, err := strconv.Atoi(cartID)
 	if err != nil {
-		h.logger.Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
-		http.Error(w, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID), http.StatusBadRequest)
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
 		return
 	}
 	carts, err := h.service.GetCart(ctx, id)
 	if err != nil {
-		var notFoundErr *Cart.ErrCartNotFound
-		if errors.As(err, &notFoundErr) {
-			h.logger.Warn("cart not found",
-				zap.Int("cart_id", id),
-			)
-			http.Error(w, notFoundErr.Error(), http.StatusNotFound)
-			return
-		}
-		var notFoundItemErr *Cart.ErrCartItemNotFound
-		if errors.As(err, &notFoundItemErr) {
-			h.logger.Warn("cart items not found",
-				zap.Int("cart_id", id),
-			)
-			http.Error(w, notFoundItemErr.Error(), http.StatusNotFound)
-			return
-		}
-		h.logger.Error("error getting carts",
-			zap.Error(err),
-			zap.Int("cart_id", id),
-		)
-		http.Error(w, "Failed to receive cart details", http.StatusInternalServerError)
+		h.writeError(w, r, err, zap.Int("cart_id", id))
+		return
+	}
+	etag := cartETag(carts.Version)
+	w.Header().Set("ETag", etag)
+	if etagListMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 	itemsDTO := make([]dto.ItemResponse, 0, len(carts.Items))
 
 	for _, item := range carts.Items {
-		itemsDTO = append(itemsDTO, dto.ItemResponse{
-			ID:      item.Id,
-			CartID:  item.CartId,
-			Product: item.Product,
-			Price:   item.Price,
-		})
+		itemsDTO = append(itemsDTO, toItemResponse(item))
 	}
 
 	resp := dto.CartResponse{
-		ID:    carts.ID,
-		Items: itemsDTO,
+		ID:       carts.ID,
+		Currency: carts.Currency,
+		Items:    itemsDTO,
 	}
 	err = json.NewEncoder(w).Encode(resp)
 	if err != nil {
-		h.logger.Error("error encoding carts", zap.Error(err))
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		h.log(ctx).Error("error encoding carts", zap.Error(err))
+		return
+	}
+}
+
+func (h *CartHandler) PostCheckout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
+		return
+	}
+	if region := r.URL.Query().Get("region"); region != "" {
+		ctx = services.WithTaxRegion(ctx, region)
+	}
+	order, err := h.service.Checkout(ctx, id)
+	if err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id))
+		return
+	}
+	h.log(ctx).Info("cart checked out",
+		zap.Int("cart_id", id),
+		zap.Int("order_id", order.ID),
+	)
+	resp := dto.OrderResponse{
+		ID:        order.ID,
+		CartID:    order.CartID,
+		Items:     make([]dto.ItemResponse, 0, len(order.Items)),
+		Price:     toPriceResponse(order.Price),
+		CreatedAt: order.CreatedAt,
+	}
+	for _, item := range order.Items {
+		resp.Items = append(resp.Items, toItemResponse(item))
+	}
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.log(ctx).Error("error encoding order", zap.Error(err))
+		return
+	}
+}
+
+func (h *CartHandler) PostBeginCheckout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
+		return
+	}
+	if region := r.URL.Query().Get("region"); region != "" {
+		ctx = services.WithTaxRegion(ctx, region)
+	}
+	price, err := h.service.BeginCheckout(ctx, id)
+	if err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id))
+		return
+	}
+	h.log(ctx).Info("checkout started", zap.Int("cart_id", id))
+	err = json.NewEncoder(w).Encode(toPriceResponse(*price))
+	if err != nil {
+		h.log(ctx).Error("error encoding price", zap.Error(err))
+		return
+	}
+}
+
+func (h *CartHandler) PostCancelCheckout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
+		return
+	}
+	if err = h.service.CancelCheckout(ctx, id); err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id))
+		return
+	}
+	h.log(ctx).Info("checkout cancelled", zap.Int("cart_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CartHandler) PostMerge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
+		return
+	}
+	var req dto.MergeCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(ctx).Error("invalid request body", zap.Error(err))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "request body must be valid JSON")
+		return
+	}
+	result, err := h.service.MergeCart(ctx, id, req.GuestCartID)
+	if err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id), zap.Int("guest_cart_id", req.GuestCartID))
+		return
+	}
+	h.log(ctx).Info("carts merged",
+		zap.Int("cart_id", id),
+		zap.Int("guest_cart_id", req.GuestCartID),
+		zap.Int("conflicts", len(result.Conflicts)),
+	)
+	resp := dto.MergeCartResponse{
+		Cart: dto.CartResponse{
+			ID:       result.Cart.ID,
+			Currency: result.Cart.Currency,
+			Items:    make([]dto.ItemResponse, 0, len(result.Cart.Items)),
+		},
+		Conflicts: make([]dto.MergeConflictResponse, 0, len(result.Conflicts)),
+	}
+	for _, item := range result.Cart.Items {
+		resp.Cart.Items = append(resp.Cart.Items, toItemResponse(item))
+	}
+	for _, conflict := range result.Conflicts {
+		resp.Conflicts = append(resp.Conflicts, dto.MergeConflictResponse{
+			Product:  conflict.Product,
+			Quantity: conflict.Quantity,
+			Reason:   conflict.Reason,
+		})
+	}
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.log(ctx).Error("error encoding merge result", zap.Error(err))
 		return
 	}
 }
@@ -234,35 +369,265 @@ func (h *CartHandler) GetPrice(w http.ResponseWriter, r *http.Request) {
 	cartID := r.PathValue("cart_id")
 	id, err := strconv.Atoi(cartID)
 	if err != nil {
-		h.logger.Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
-		http.Error(w, fmt.Sprintf("Invalid cart_id '%s': must be an integer", cartID), http.StatusBadRequest)
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
 		return
 	}
+	if region := r.URL.Query().Get("region"); region != "" {
+		ctx = services.WithTaxRegion(ctx, region)
+	}
+	if currency := r.URL.Query().Get("currency"); currency != "" {
+		ctx = services.WithDisplayCurrency(ctx, currency)
+	}
 	price, err := h.service.GetPrice(ctx, id)
 	if err != nil {
-		var notFoundErr *Cart.ErrCartNotFound
-		if errors.As(err, &notFoundErr) {
-			h.logger.Warn("cart not found for price calculation", zap.Int("id", id))
-			http.Error(w, fmt.Sprintf("Cart with id %d not found", id), http.StatusNotFound)
-			return
-		}
-		h.logger.Error("error calculating price",
-			zap.Error(err),
-			zap.Int("cart_id", id),
-		)
-		http.Error(w, "Failed to calculate cart price", http.StatusInternalServerError)
+		h.writeError(w, r, err, zap.Int("cart_id", id))
+		return
+	}
+	err = json.NewEncoder(w).Encode(toPriceResponse(*price))
+	if err != nil {
+		h.log(ctx).Error("error encoding price", zap.Error(err))
+		return
+	}
+}
+
+func (h *CartHandler) GetShippingOptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
+		return
+	}
+	options, err := h.service.ShippingOptions(ctx, id)
+	if err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id))
+		return
+	}
+	resp := make([]dto.ShippingOptionResponse, 0, len(options))
+	for _, option := range options {
+		resp = append(resp, toShippingOptionResponse(option))
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		h.log(ctx).Error("error encoding shipping options", zap.Error(err))
+		return
+	}
+}
+
+func (h *CartHandler) PutShipping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
+		return
+	}
+	var req dto.SelectShippingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(ctx).Error("invalid request body", zap.Error(err))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "request body must be valid JSON")
+		return
+	}
+	ctx, ok := withIfMatch(ctx, r)
+	if !ok {
+		problem.Error(w, r, http.StatusPreconditionFailed, problem.CodeVersionMismatch, "If-Match does not match the current cart version")
+		return
+	}
+	option, err := h.service.SelectShipping(ctx, id, req.Method)
+	if err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id))
+		return
+	}
+	h.log(ctx).Info("shipping method selected",
+		zap.Int("cart_id", id),
+		zap.String("method", option.Method),
+	)
+	if err = json.NewEncoder(w).Encode(toShippingOptionResponse(*option)); err != nil {
+		h.log(ctx).Error("error encoding shipping option", zap.Error(err))
+		return
+	}
+}
+
+func (h *CartHandler) PostCoupon(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
+		return
+	}
+	var req dto.ApplyCouponRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(ctx).Error("invalid request body", zap.Error(err))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "request body must be valid JSON")
+		return
+	}
+	coupon, err := h.service.ApplyCoupon(ctx, id, req.Code)
+	if err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id), zap.String("coupon", req.Code))
+		return
+	}
+	h.log(ctx).Info("coupon applied",
+		zap.Int("cart_id", id),
+		zap.String("code", coupon.Code),
+	)
+	resp := dto.CouponResponse{
+		CartID:    id,
+		Code:      coupon.Code,
+		Percent:   coupon.Percent,
+		Amount:    coupon.Amount,
+		MinTotal:  coupon.MinTotal,
+		Currency:  coupon.Amount.Currency,
+		Stackable: coupon.Stackable,
+	}
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		h.log(ctx).Error("error encoding coupon", zap.Error(err))
 		return
 	}
+}
+
+func (h *CartHandler) DeleteCoupon(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	cartID := r.PathValue("cart_id")
+	code := r.PathValue("code")
+	id, err := strconv.Atoi(cartID)
+	if err != nil {
+		h.log(ctx).Error("failed to parse cart id", zap.Error(err), zap.String("input", cartID))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidCartID, fmt.Sprintf("invalid cart ID; '%s' must be an integer", cartID))
+		return
+	}
+	if err = h.service.RemoveCoupon(ctx, id, code); err != nil {
+		h.writeError(w, r, err, zap.Int("cart_id", id), zap.String("coupon", code))
+		return
+	}
+	h.log(ctx).Info("coupon removed",
+		zap.Int("cart_id", id),
+		zap.String("code", code),
+	)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *CartHandler) log(ctx context.Context) *zap.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
+
+func (h *CartHandler) writeError(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	writeError(w, r, h.log(r.Context()), err, fields...)
+}
+
+func cartETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func etagListMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// withIfMatch turns an If-Match header into an expected cart version. It
+// reports false when the header cannot match any cart version.
+func withIfMatch(ctx context.Context, r *http.Request) (context.Context, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return ctx, true
+	}
+	version, err := strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return ctx, false
+	}
+	return services.WithExpectedVersion(ctx, version), true
+}
+
+func toPriceResponse(price model.Price) dto.PriceResponse {
 	resp := dto.PriceResponse{
 		CartID:          price.CartId,
+		Currency:        price.TotalPrice.Currency,
 		TotalPrice:      price.TotalPrice,
 		DiscountPercent: price.DiscountPercent,
+		DiscountAmount:  price.DiscountAmount,
+		Discounts:       make([]dto.AppliedDiscountResponse, 0, len(price.Discounts)),
+		Coupons:         make([]dto.AppliedCouponResponse, 0, len(price.Coupons)),
 		FinalPrice:      price.FinalPrice,
+		Region:          price.Region,
+		TaxMode:         price.TaxMode,
+		Subtotal:        price.Subtotal,
+		Taxes:           make([]dto.TaxLineResponse, 0, len(price.Taxes)),
+		TaxTotal:        price.TaxTotal,
+		GrandTotal:      price.GrandTotal,
 	}
-	err = json.NewEncoder(w).Encode(resp)
-	if err != nil {
-		h.logger.Error("error encoding price", zap.Error(err))
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
+	for _, discount := range price.Discounts {
+		resp.Discounts = append(resp.Discounts, dto.AppliedDiscountResponse{
+			Rule:   discount.Rule,
+			Amount: discount.Amount,
+		})
+	}
+	for _, coupon := range price.Coupons {
+		resp.Coupons = append(resp.Coupons, dto.AppliedCouponResponse{
+			Code:   coupon.Code,
+			Amount: coupon.Amount,
+		})
+	}
+	if price.Shipping != nil {
+		resp.Shipping = &dto.AppliedShippingResponse{
+			Method: price.Shipping.Method,
+			Amount: price.Shipping.Amount,
+		}
+	}
+	if price.Exchange != nil {
+		resp.Exchange = &dto.ExchangeRateResponse{
+			From:      price.Exchange.From,
+			To:        price.Exchange.To,
+			Rate:      json.Number(exchange.FormatRate(price.Exchange.Rate)),
+			UpdatedAt: price.Exchange.UpdatedAt,
+		}
+	}
+	for _, tax := range price.Taxes {
+		resp.Taxes = append(resp.Taxes, dto.TaxLineResponse{
+			Category: tax.Category,
+			Rate:     json.Number(pricing.FormatRate(tax.Rate)),
+			Taxable:  tax.Taxable,
+			Amount:   tax.Amount,
+		})
+	}
+	return resp
+}
+
+func toShippingOptionResponse(option model.ShippingOption) dto.ShippingOptionResponse {
+	return dto.ShippingOptionResponse{
+		Method:   option.Method,
+		Name:     option.Name,
+		Price:    option.Price,
+		Currency: option.Price.Currency,
+		Selected: option.Selected,
+	}
+}
+
+func toItemResponse(item model.CartItem) dto.ItemResponse {
+	return dto.ItemResponse{
+		ID:       item.Id,
+		CartID:   item.CartId,
+		SKU:      item.SKU,
+		Product:  item.Product,
+		Price:    item.Price,
+		Currency: item.Price.Currency,
+		Quantity: item.Quantity,
 	}
 }
@@ -4,54 +4,137 @@ import (
 	"bytes"
 	"cart-api/internal/model"
 	"cart-api/internal/repository/Cart"
+	"cart-api/internal/repository/Coupon"
+	"cart-api/internal/services"
+	"cart-api/internal/transport/dto"
+	"cart-api/internal/transport/problem"
+	"cart-api/pkg/logger"
+	"cart-api/pkg/money"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
+func usd(amount string) money.Money {
+	return money.MustParse(amount, money.DefaultCurrency)
+}
+
 type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) CreateCart() (*model.Cart, error) {
-	args := m.Called()
+func (m *MockService) CreateCart(ctx context.Context) (*model.Cart, error) {
+	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Cart), args.Error(1)
 }
 
-func (m *MockService) CreateItem(item model.CartItem) (int, error) {
-	args := m.Called(item)
-	return args.Int(0), args.Error(1)
+func (m *MockService) CreateItem(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CartItem), args.Error(1)
+}
+
+func (m *MockService) UpdateItem(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CartItem), args.Error(1)
 }
 
-func (m *MockService) DeleteItem(item model.CartItem) error {
-	args := m.Called(item)
+func (m *MockService) DeleteItem(ctx context.Context, item model.CartItem) error {
+	args := m.Called(ctx, item)
 	return args.Error(0)
 }
 
-func (m *MockService) GetCart(id int) (*model.Cart, error) {
-	args := m.Called(id)
+func (m *MockService) GetCart(ctx context.Context, id int) (*model.Cart, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Cart), args.Error(1)
 }
 
-func (m *MockService) GetPrice(id int) (*model.Price, error) {
-	args := m.Called(id)
+func (m *MockService) GetPrice(ctx context.Context, id int) (*model.Price, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Price), args.Error(1)
+}
+
+func (m *MockService) ApplyCoupon(ctx context.Context, cartID int, code string) (*model.Coupon, error) {
+	args := m.Called(ctx, cartID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Coupon), args.Error(1)
+}
+
+func (m *MockService) RemoveCoupon(ctx context.Context, cartID int, code string) error {
+	args := m.Called(ctx, cartID, code)
+	return args.Error(0)
+}
+
+func (m *MockService) MergeCart(ctx context.Context, cartID, guestCartID int) (*model.MergeResult, error) {
+	args := m.Called(ctx, cartID, guestCartID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MergeResult), args.Error(1)
+}
+
+func (m *MockService) BeginCheckout(ctx context.Context, cartID int) (*model.Price, error) {
+	args := m.Called(ctx, cartID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*model.Price), args.Error(1)
 }
 
+func (m *MockService) Checkout(ctx context.Context, cartID int) (*model.Order, error) {
+	args := m.Called(ctx, cartID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Order), args.Error(1)
+}
+
+func (m *MockService) CancelCheckout(ctx context.Context, cartID int) error {
+	return m.Called(ctx, cartID).Error(0)
+}
+
+func (m *MockService) ShippingOptions(ctx context.Context, cartID int) ([]model.ShippingOption, error) {
+	args := m.Called(ctx, cartID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ShippingOption), args.Error(1)
+}
+
+func (m *MockService) SelectShipping(ctx context.Context, cartID int, method string) (*model.ShippingOption, error) {
+	args := m.Called(ctx, cartID, method)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ShippingOption), args.Error(1)
+}
+
 func TestCartHandler_PostCart(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	mockSvc := new(MockService)
@@ -59,7 +142,7 @@ func TestCartHandler_PostCart(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		expectedCart := &model.Cart{ID: 100}
-		mockSvc.On("CreateCart").Return(expectedCart, nil).Once()
+		mockSvc.On("CreateCart", mock.Anything).Return(expectedCart, nil).Once()
 
 		req := httptest.NewRequest(http.MethodPost, "/carts", nil)
 		w := httptest.NewRecorder()
@@ -74,7 +157,7 @@ func TestCartHandler_PostCart(t *testing.T) {
 	})
 
 	t.Run("Service Error", func(t *testing.T) {
-		mockSvc.On("CreateCart").Return(nil, errors.New("db fail")).Once()
+		mockSvc.On("CreateCart", mock.Anything).Return(nil, errors.New("db fail")).Once()
 
 		req := httptest.NewRequest(http.MethodPost, "/carts", nil)
 		w := httptest.NewRecorder()
@@ -93,8 +176,7 @@ func TestCartHandler_PostItem(t *testing.T) {
 	mockSvc := new(MockService)
 	handler := NewCartHandler(mockSvc, logger)
 
-	validItem := model.CartItem{Product: "Apple", Price: 50}
-	bodyJSON, _ := json.Marshal(validItem)
+	bodyJSON, _ := json.Marshal(dto.AddItemRequest{SKU: "APL-1"})
 
 	tests := []struct {
 		name           string
@@ -109,9 +191,9 @@ func TestCartHandler_PostItem(t *testing.T) {
 			cartID: "1",
 			body:   bodyJSON,
 			setupMock: func() {
-				mockSvc.On("CreateItem", mock.MatchedBy(func(i model.CartItem) bool {
-					return i.Product == "Apple" && i.CartId == 1
-				})).Return(555, nil)
+				mockSvc.On("CreateItem", mock.Anything, mock.MatchedBy(func(i model.CartItem) bool {
+					return i.SKU == "APL-1" && i.CartId == 1
+				})).Return(&model.CartItem{Id: 555, CartId: 1, SKU: "APL-1", Product: "Apple", Price: usd("50"), Quantity: 1}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   `"id":555`,
@@ -123,34 +205,69 @@ func TestCartHandler_PostItem(t *testing.T) {
 			setupMock: func() {
 			},
 			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"invalid_cart_id"`,
 		},
 		{
 			name:   "Business Limit Error",
 			cartID: "1",
 			body:   bodyJSON,
 			setupMock: func() {
-				mockSvc.On("CreateItem", mock.Anything).Return(0, errors.New("cart cannot consist more than 5 distinct products"))
+				mockSvc.On("CreateItem", mock.Anything, mock.Anything).Return(nil, services.ErrReachCartLimit)
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "limit reached",
 		},
+		{
+			name:   "Unknown SKU",
+			cartID: "1",
+			body:   bodyJSON,
+			setupMock: func() {
+				mockSvc.On("CreateItem", mock.Anything, mock.Anything).Return(nil, services.ErrUnknownProduct)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "unknown product",
+		},
+		{
+			name:   "Insufficient Stock",
+			cartID: "1",
+			body:   bodyJSON,
+			setupMock: func() {
+				mockSvc.On("CreateItem", mock.Anything, mock.Anything).
+					Return(nil, &services.InsufficientStockError{SKU: "APL-1", Requested: 3, Available: 2})
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `"available":2`,
+		},
+		{
+			name:   "Structured Limit Error",
+			cartID: "1",
+			body:   bodyJSON,
+			setupMock: func() {
+				mockSvc.On("CreateItem", mock.Anything, mock.Anything).
+					Return(nil, &services.LimitError{Limit: services.LimitUnits, Max: "20", Actual: "21"})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"cart_limit_exceeded","actual":21,"limit":"max_units","max":20}`,
+		},
 		{
 			name:   "Not Found Error",
 			cartID: "99",
 			body:   bodyJSON,
 			setupMock: func() {
-				mockSvc.On("CreateItem", mock.Anything).Return(0, errors.New("something does not exist"))
+				mockSvc.On("CreateItem", mock.Anything, mock.Anything).Return(nil, services.ErrCartNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
+			expectedBody:   `"code":"cart_not_found"`,
 		},
 		{
 			name:   "Internal Error",
 			cartID: "1",
 			body:   bodyJSON,
 			setupMock: func() {
-				mockSvc.On("CreateItem", mock.Anything).Return(0, errors.New("unknown db error"))
+				mockSvc.On("CreateItem", mock.Anything, mock.Anything).Return(nil, errors.New("unknown db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `"code":"internal_error"`,
 		},
 	}
 
@@ -175,6 +292,78 @@ func TestCartHandler_PostItem(t *testing.T) {
 	}
 }
 
+func TestCartHandler_PatchItem(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	mockSvc := new(MockService)
+	handler := NewCartHandler(mockSvc, logger)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			path: "/carts/1/items/7",
+			body: `{"quantity":3}`,
+			setupMock: func() {
+				mockSvc.On("UpdateItem", mock.Anything, model.CartItem{Id: 7, CartId: 1, Quantity: 3}).
+					Return(&model.CartItem{Id: 7, CartId: 1, Product: "Apple", Price: usd("50"), Quantity: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"quantity":3`,
+		},
+		{
+			name:           "Invalid Item ID",
+			path:           "/carts/1/items/abc",
+			body:           `{"quantity":3}`,
+			setupMock:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid Quantity",
+			path: "/carts/1/items/7",
+			body: `{"quantity":0}`,
+			setupMock: func() {
+				mockSvc.On("UpdateItem", mock.Anything, mock.Anything).Return(nil, services.ErrInvalidQuantity)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Item Not Found",
+			path: "/carts/1/items/7",
+			body: `{"quantity":2}`,
+			setupMock: func() {
+				mockSvc.On("UpdateItem", mock.Anything, mock.Anything).Return(nil, Cart.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPatch, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PATCH /carts/{cart_id}/items/{item_id}", handler.PatchItem)
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockSvc.AssertExpectations(t)
+			mockSvc.ExpectedCalls = nil
+		})
+	}
+}
+
 func TestCartHandler_GetItems(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	mockSvc := new(MockService)
@@ -182,7 +371,7 @@ func TestCartHandler_GetItems(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		cart := &model.Cart{ID: 1, Items: []model.CartItem{{Product: "Banana"}}}
-		mockSvc.On("GetCart", 1).Return(cart, nil)
+		mockSvc.On("GetCart", mock.Anything, 1).Return(cart, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/carts/1", nil)
 		w := httptest.NewRecorder()
@@ -198,7 +387,7 @@ func TestCartHandler_GetItems(t *testing.T) {
 
 	t.Run("Not Found (Custom Error Type)", func(t *testing.T) {
 		notFoundErr := &Cart.ErrCartNotFound{ID: 999}
-		mockSvc.On("GetCart", 999).Return(nil, notFoundErr)
+		mockSvc.On("GetCart", mock.Anything, 999).Return(nil, notFoundErr)
 
 		req := httptest.NewRequest(http.MethodGet, "/carts/999", nil)
 		w := httptest.NewRecorder()
@@ -210,6 +399,34 @@ func TestCartHandler_GetItems(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 		mockSvc.AssertExpectations(t)
 	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		mockSvc.On("GetCart", mock.Anything, 2).Return(nil, services.ErrForbidden)
+
+		req := httptest.NewRequest(http.MethodGet, "/carts/2", nil)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /carts/{cart_id}", handler.GetItems)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		mockSvc.On("GetCart", mock.Anything, 3).Return(nil, services.ErrUnauthenticated)
+
+		req := httptest.NewRequest(http.MethodGet, "/carts/3", nil)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /carts/{cart_id}", handler.GetItems)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
 }
 
 func TestCartHandler_DeleteItem(t *testing.T) {
@@ -219,7 +436,7 @@ func TestCartHandler_DeleteItem(t *testing.T) {
 		mockSvc := new(MockService)
 		handler := NewCartHandler(mockSvc, logger)
 
-		mockSvc.On("DeleteItem", mock.MatchedBy(func(i model.CartItem) bool {
+		mockSvc.On("DeleteItem", mock.Anything, mock.MatchedBy(func(i model.CartItem) bool {
 			return i.CartId == 1 && i.Id == 5
 		})).Return(nil)
 
@@ -238,7 +455,7 @@ func TestCartHandler_DeleteItem(t *testing.T) {
 		mockSvc := new(MockService)
 		handler := NewCartHandler(mockSvc, logger)
 
-		mockSvc.On("DeleteItem", mock.Anything).Return(Cart.ErrNotFound)
+		mockSvc.On("DeleteItem", mock.Anything, mock.Anything).Return(Cart.ErrNotFound)
 
 		req := httptest.NewRequest(http.MethodDelete, "/carts/1/items/5", nil)
 		w := httptest.NewRecorder()
@@ -258,8 +475,8 @@ func TestCartHandler_GetPrice(t *testing.T) {
 	handler := NewCartHandler(mockSvc, logger)
 
 	t.Run("Success", func(t *testing.T) {
-		price := &model.Price{FinalPrice: 1000}
-		mockSvc.On("GetPrice", 1).Return(price, nil)
+		price := &model.Price{FinalPrice: usd("1000")}
+		mockSvc.On("GetPrice", mock.Anything, 1).Return(price, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/carts/1/price", nil)
 		w := httptest.NewRecorder()
@@ -272,4 +489,587 @@ func TestCartHandler_GetPrice(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "1000")
 		mockSvc.AssertExpectations(t)
 	})
+
+	t.Run("Tax Region", func(t *testing.T) {
+		mockSvc := new(MockService)
+		handler := NewCartHandler(mockSvc, logger)
+		price := &model.Price{
+			FinalPrice: usd("100.00"),
+			Region:     "DE",
+			Taxes:      []model.TaxLine{{Category: "standard", Rate: 190000, Taxable: usd("100.00"), Amount: usd("19.00")}},
+			TaxTotal:   usd("19.00"),
+			GrandTotal: usd("119.00"),
+		}
+		mockSvc.On("GetPrice", mock.MatchedBy(func(ctx context.Context) bool {
+			region, ok := services.TaxRegion(ctx)
+			return ok && region == "DE"
+		}), 1).Return(price, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/carts/1/price?region=de", nil)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /carts/{cart_id}/price", handler.GetPrice)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"rate":19,`)
+		assert.Contains(t, w.Body.String(), `"grand_total":119.00`)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Region", func(t *testing.T) {
+		mockSvc := new(MockService)
+		handler := NewCartHandler(mockSvc, logger)
+		mockSvc.On("GetPrice", mock.Anything, 1).Return(nil, services.ErrUnknownTaxRegion)
+
+		req := httptest.NewRequest(http.MethodGet, "/carts/1/price?region=xx", nil)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /carts/{cart_id}/price", handler.GetPrice)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Currency", func(t *testing.T) {
+		mockSvc := new(MockService)
+		handler := NewCartHandler(mockSvc, logger)
+		eur := money.MustParse("92.00", "EUR")
+		price := &model.Price{
+			TotalPrice: eur,
+			FinalPrice: eur,
+			GrandTotal: eur,
+			Exchange: &model.AppliedExchangeRate{
+				From:      "USD",
+				To:        "EUR",
+				Rate:      92_000_000,
+				UpdatedAt: time.Date(2026, 4, 12, 16, 0, 0, 0, time.UTC),
+			},
+		}
+		mockSvc.On("GetPrice", mock.MatchedBy(func(ctx context.Context) bool {
+			currency, ok := services.DisplayCurrency(ctx)
+			return ok && currency == "EUR"
+		}), 1).Return(price, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/carts/1/price?currency=eur", nil)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /carts/{cart_id}/price", handler.GetPrice)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"currency":"EUR"`)
+		assert.Contains(t, w.Body.String(), `"exchange":{"from":"USD","to":"EUR","rate":0.92,"updated_at":"2026-04-12T16:00:00Z"}`)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Exchange Rate", func(t *testing.T) {
+		mockSvc := new(MockService)
+		handler := NewCartHandler(mockSvc, logger)
+		mockSvc.On("GetPrice", mock.Anything, 1).Return(nil, services.ErrUnknownExchangeRate)
+
+		req := httptest.NewRequest(http.MethodGet, "/carts/1/price?currency=CHF", nil)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /carts/{cart_id}/price", handler.GetPrice)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCartHandler_Shipping(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMock      func(*MockService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "List Options",
+			method: http.MethodGet,
+			path:   "/carts/1/shipping-options",
+			setupMock: func(m *MockService) {
+				m.On("ShippingOptions", mock.Anything, 1).Return([]model.ShippingOption{
+					{Method: "standard", Name: "Standard", Price: usd("4.99"), Selected: true},
+					{Method: "express", Name: "Express", Price: usd("24.99")},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"method":"standard","name":"Standard","price":4.99,"currency":"USD","selected":true`,
+		},
+		{
+			name:   "Select Method",
+			method: http.MethodPut,
+			path:   "/carts/1/shipping",
+			body:   `{"method":"express"}`,
+			setupMock: func(m *MockService) {
+				m.On("SelectShipping", mock.Anything, 1, "express").
+					Return(&model.ShippingOption{Method: "express", Name: "Express", Price: usd("24.99"), Selected: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"method":"express"`,
+		},
+		{
+			name:   "Unknown Method",
+			method: http.MethodPut,
+			path:   "/carts/1/shipping",
+			body:   `{"method":"drone"}`,
+			setupMock: func(m *MockService) {
+				m.On("SelectShipping", mock.Anything, 1, "drone").Return(nil, services.ErrUnknownShippingMethod)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "unknown shipping method",
+		},
+		{
+			name:   "Cart Not Found",
+			method: http.MethodGet,
+			path:   "/carts/9/shipping-options",
+			setupMock: func(m *MockService) {
+				m.On("ShippingOptions", mock.Anything, 9).Return(nil, services.ErrCartNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockService)
+			tt.setupMock(mockSvc)
+			handler := NewCartHandler(mockSvc, logger)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /carts/{cart_id}/shipping-options", handler.GetShippingOptions)
+			mux.HandleFunc("PUT /carts/{cart_id}/shipping", handler.PutShipping)
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartHandler_PostCoupon(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			body: `{"code":"save10"}`,
+			setupMock: func(m *MockService) {
+				m.On("ApplyCoupon", mock.Anything, 1, "save10").
+					Return(&model.Coupon{Code: "SAVE10", Percent: 10}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"code":"SAVE10"`,
+		},
+		{
+			name: "Not Found",
+			body: `{"code":"nope"}`,
+			setupMock: func(m *MockService) {
+				m.On("ApplyCoupon", mock.Anything, 1, "nope").Return(nil, Coupon.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Not Stackable",
+			body: `{"code":"solo"}`,
+			setupMock: func(m *MockService) {
+				m.On("ApplyCoupon", mock.Anything, 1, "solo").Return(nil, services.ErrCouponNotStackable)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "Expired",
+			body: `{"code":"old"}`,
+			setupMock: func(m *MockService) {
+				m.On("ApplyCoupon", mock.Anything, 1, "old").Return(nil, services.ErrCouponExpired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "expired",
+		},
+		{
+			name:           "Invalid JSON",
+			body:           `{`,
+			setupMock:      func(m *MockService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockService)
+			handler := NewCartHandler(mockSvc, logger)
+			tt.setupMock(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/carts/1/coupons", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /carts/{cart_id}/coupons", handler.PostCoupon)
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartHandler_DeleteCoupon(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	t.Run("Success", func(t *testing.T) {
+		mockSvc := new(MockService)
+		handler := NewCartHandler(mockSvc, logger)
+		mockSvc.On("RemoveCoupon", mock.Anything, 1, "SAVE10").Return(nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/carts/1/coupons/SAVE10", nil)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("DELETE /carts/{cart_id}/coupons/{code}", handler.DeleteCoupon)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Not Applied", func(t *testing.T) {
+		mockSvc := new(MockService)
+		handler := NewCartHandler(mockSvc, logger)
+		mockSvc.On("RemoveCoupon", mock.Anything, 1, "SAVE10").Return(Coupon.ErrNotApplied)
+
+		req := httptest.NewRequest(http.MethodDelete, "/carts/1/coupons/SAVE10", nil)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("DELETE /carts/{cart_id}/coupons/{code}", handler.DeleteCoupon)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+}
+
+func TestCartHandler_PostMerge(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name         string
+		body         string
+		mockBehavior func(m *MockService)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "Success With Conflicts",
+			body: `{"guest_cart_id": 2}`,
+			mockBehavior: func(m *MockService) {
+				m.On("MergeCart", mock.Anything, 1, 2).Return(&model.MergeResult{
+					Cart:      &model.Cart{ID: 1, Items: []model.CartItem{{Id: 3, CartId: 1, Product: "Apple", Price: usd("12.00"), Quantity: 3}}},
+					Conflicts: []model.MergeConflict{{Product: "Kiwi", Quantity: 1, Reason: services.ErrReachCartLimit.Error()}},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"product":"Kiwi"`,
+		},
+		{
+			name: "Same Cart",
+			body: `{"guest_cart_id": 1}`,
+			mockBehavior: func(m *MockService) {
+				m.On("MergeCart", mock.Anything, 1, 1).Return(nil, services.ErrMergeSameCart)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Forbidden",
+			body: `{"guest_cart_id": 2}`,
+			mockBehavior: func(m *MockService) {
+				m.On("MergeCart", mock.Anything, 1, 2).Return(nil, services.ErrForbidden)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "Guest Cart Not Found",
+			body: `{"guest_cart_id": 2}`,
+			mockBehavior: func(m *MockService) {
+				m.On("MergeCart", mock.Anything, 1, 2).Return(nil, services.ErrCartNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "Invalid JSON",
+			body:         `{`,
+			mockBehavior: func(m *MockService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockService)
+			tt.mockBehavior(mockSvc)
+			handler := NewCartHandler(mockSvc, logger)
+
+			req := httptest.NewRequest(http.MethodPost, "/carts/1/merge", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /carts/{cart_id}/merge", handler.PostMerge)
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartHandler_PostCheckout(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "Success", expectedCode: http.StatusCreated},
+		{name: "Not Open", err: services.ErrCartNotOpen, expectedCode: http.StatusConflict},
+		{name: "Empty", err: services.ErrCartEmpty, expectedCode: http.StatusBadRequest},
+		{name: "Not Found", err: services.ErrCartNotFound, expectedCode: http.StatusNotFound},
+		{name: "Internal", err: errors.New("db error"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockService)
+			if tt.err != nil {
+				mockSvc.On("Checkout", mock.Anything, 1).Return(nil, tt.err)
+			} else {
+				mockSvc.On("Checkout", mock.Anything, 1).Return(&model.Order{
+					ID:     7,
+					CartID: 1,
+					Items:  []model.CartItem{{Id: 1, CartId: 1, Product: "Shoes", Price: usd("10.00"), Quantity: 1}},
+					Price:  model.Price{CartId: 1, TotalPrice: usd("10.00"), FinalPrice: usd("10.00")},
+				}, nil)
+			}
+			handler := NewCartHandler(mockSvc, logger)
+
+			req := httptest.NewRequest(http.MethodPost, "/carts/1/checkout", nil)
+			w := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /carts/{cart_id}/checkout", handler.PostCheckout)
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.err == nil {
+				assert.Contains(t, w.Body.String(), `"final_price":10.00`)
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartHandler_PostBeginCheckout(t *testing.T) {
+	mockSvc := new(MockService)
+	mockSvc.On("BeginCheckout", mock.Anything, 1).Return(&model.Price{CartId: 1, TotalPrice: usd("10.00"), FinalPrice: usd("10.00")}, nil)
+	handler := NewCartHandler(mockSvc, zaptest.NewLogger(t))
+
+	req := httptest.NewRequest(http.MethodPost, "/carts/1/checkout/begin", nil)
+	w := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /carts/{cart_id}/checkout/begin", handler.PostBeginCheckout)
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"final_price":10.00`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCartHandler_PostCancelCheckout(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "Success", expectedCode: http.StatusNoContent},
+		{name: "Not Started", err: services.ErrCheckoutNotStarted, expectedCode: http.StatusConflict, expectedBody: problem.CodeCheckoutNotStarted},
+		{name: "Already Ordered", err: services.ErrCartNotOpen, expectedCode: http.StatusConflict, expectedBody: problem.CodeCartNotOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockService)
+			mockSvc.On("CancelCheckout", mock.Anything, 1).Return(tt.err)
+			handler := NewCartHandler(mockSvc, zaptest.NewLogger(t))
+
+			req := httptest.NewRequest(http.MethodPost, "/carts/1/checkout/cancel", nil)
+			w := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /carts/{cart_id}/checkout/cancel", handler.PostCancelCheckout)
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartHandler_PostItemCartNotOpen(t *testing.T) {
+	mockSvc := new(MockService)
+	mockSvc.On("CreateItem", mock.Anything, mock.Anything).Return(nil, services.ErrCartNotOpen)
+	handler := NewCartHandler(mockSvc, zaptest.NewLogger(t))
+
+	req := httptest.NewRequest(http.MethodPost, "/carts/1/items", bytes.NewBufferString(`{"sku": "SHO-1"}`))
+	w := httptest.NewRecorder()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /carts/{cart_id}/items", handler.PostItem)
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCartHandler_ETag(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	cart := &model.Cart{ID: 1, Version: 3, Items: []model.CartItem{{Product: "Banana"}}}
+
+	tests := []struct {
+		name         string
+		ifNoneMatch  string
+		expectedCode int
+	}{
+		{name: "No Precondition", expectedCode: http.StatusOK},
+		{name: "Matching", ifNoneMatch: `"3"`, expectedCode: http.StatusNotModified},
+		{name: "Matching In List", ifNoneMatch: `"1", W/"3"`, expectedCode: http.StatusNotModified},
+		{name: "Stale", ifNoneMatch: `"2"`, expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockService)
+			mockSvc.On("GetCart", mock.Anything, 1).Return(cart, nil)
+			handler := NewCartHandler(mockSvc, logger)
+
+			req := httptest.NewRequest(http.MethodGet, "/carts/1", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /carts/{cart_id}", handler.GetItems)
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+			if tt.expectedCode == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCartHandler_IfMatch(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	t.Run("Expected Version Passed To Service", func(t *testing.T) {
+		mockSvc := new(MockService)
+		mockSvc.On("CreateItem", mock.MatchedBy(func(ctx context.Context) bool {
+			version, ok := services.ExpectedVersion(ctx)
+			return ok && version == 4
+		}), mock.Anything).Return(&model.CartItem{Id: 1, CartId: 1}, nil)
+		handler := NewCartHandler(mockSvc, logger)
+
+		req := httptest.NewRequest(http.MethodPost, "/carts/1/items", bytes.NewBufferString(`{"sku": "SHO-1"}`))
+		req.Header.Set("If-Match", `"4"`)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /carts/{cart_id}/items", handler.PostItem)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Version Mismatch", func(t *testing.T) {
+		mockSvc := new(MockService)
+		mockSvc.On("DeleteItem", mock.Anything, mock.Anything).Return(services.ErrVersionMismatch)
+		handler := NewCartHandler(mockSvc, logger)
+
+		req := httptest.NewRequest(http.MethodDelete, "/carts/1/items/5", nil)
+		req.Header.Set("If-Match", `"2"`)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("DELETE /carts/{cart_id}/items/{item_id}", handler.DeleteItem)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("Weak ETag Never Matches", func(t *testing.T) {
+		mockSvc := new(MockService)
+		handler := NewCartHandler(mockSvc, logger)
+
+		req := httptest.NewRequest(http.MethodDelete, "/carts/1/items/5", nil)
+		req.Header.Set("If-Match", `W/"2"`)
+		w := httptest.NewRecorder()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("DELETE /carts/{cart_id}/items/{item_id}", handler.DeleteItem)
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+}
+
+func TestCartHandler_RequestLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	handler := NewCartHandler(new(MockService), zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodGet, "/carts/abc", nil)
+	req = req.WithContext(logger.WithContext(req.Context(), zap.New(core).With(zap.String("request_id", "req-9"))))
+	w := httptest.NewRecorder()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /carts/{cart_id}", handler.GetItems)
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	entries := logs.FilterField(zap.String("request_id", "req-9")).All()
+	assert.NotEmpty(t, entries)
 }
@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"cart-api/internal/transport/dto"
+	"cart-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+
+	// ShutdownCheck is the readiness check that fails once Drain was called.
+	ShutdownCheck = "shutdown"
+
+	healthCheckTimeout = 2 * time.Second
+)
+
+var errShuttingDown = errors.New("server is shutting down")
+
+// HealthCheck is a named dependency probed by the readiness endpoint.
+type HealthCheck struct {
+	Name  string
+	Check func(context.Context) error
+}
+
+type HealthHandler struct {
+	checks   []HealthCheck
+	draining atomic.Bool
+	logger   *zap.Logger
+}
+
+func NewHealthHandler(l *zap.Logger, checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+		logger: l,
+	}
+}
+
+// Drain makes the readiness endpoint fail so load balancers stop routing new
+// requests while in-flight ones are finished.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// GetLive reports that the process is up and serving HTTP. It does not touch
+// any dependency.
+func (h *HealthHandler) GetLive(w http.ResponseWriter, r *http.Request) {
+	h.writeHealth(w, r, http.StatusOK, dto.HealthResponse{Status: HealthStatusOK})
+}
+
+// GetReady runs every check concurrently and answers 503 when any of them
+// fails or the server is draining.
+func (h *HealthHandler) GetReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	results := make(map[string]dto.HealthCheckResponse, len(h.checks)+1)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := check.Check(ctx)
+			result := healthResult(err, time.Since(start))
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	var draining error
+	if h.draining.Load() {
+		draining = errShuttingDown
+	}
+	results[ShutdownCheck] = healthResult(draining, 0)
+
+	resp := dto.HealthResponse{Status: HealthStatusOK, Checks: results}
+	status := http.StatusOK
+	for name, result := range results {
+		if result.Status == HealthStatusOK {
+			continue
+		}
+		resp.Status = HealthStatusFail
+		status = http.StatusServiceUnavailable
+		h.log(ctx).Warn("readiness check failed", zap.String("check", name), zap.String("error", result.Error))
+	}
+	h.writeHealth(w, r, status, resp)
+}
+
+func (h *HealthHandler) writeHealth(w http.ResponseWriter, r *http.Request, status int, resp dto.HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log(r.Context()).Error("error encoding health", zap.Error(err))
+	}
+}
+
+func (h *HealthHandler) log(ctx context.Context) *zap.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
+
+func healthResult(err error, took time.Duration) dto.HealthCheckResponse {
+	result := dto.HealthCheckResponse{Status: HealthStatusOK, DurationMS: took.Milliseconds()}
+	if err != nil {
+		result.Status = HealthStatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"cart-api/internal/transport/dto"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func serveHealth(t *testing.T, handler http.HandlerFunc, path string) (int, dto.HealthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp dto.HealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return rec.Code, resp
+}
+
+func TestHealthHandler_GetLive(t *testing.T) {
+	h := NewHealthHandler(zaptest.NewLogger(t), HealthCheck{
+		Name:  "database",
+		Check: func(context.Context) error { return errors.New("must not be called") },
+	})
+	h.Drain()
+
+	code, resp := serveHealth(t, h.GetLive, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusOK, resp.Status)
+	assert.Empty(t, resp.Checks)
+}
+
+func TestHealthHandler_GetReady(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+
+	tests := []struct {
+		name       string
+		migrations func(context.Context) error
+		drain      bool
+		wantCode   int
+		wantStatus string
+		wantFailed map[string]string
+	}{
+		{
+			name:       "all checks pass",
+			migrations: ok,
+			wantCode:   http.StatusOK,
+			wantStatus: HealthStatusOK,
+		},
+		{
+			name: "check fails",
+			migrations: func(context.Context) error {
+				return errors.New("database schema at version 1, expected 2")
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: HealthStatusFail,
+			wantFailed: map[string]string{"migrations": "database schema at version 1, expected 2"},
+		},
+		{
+			name:       "draining",
+			migrations: ok,
+			drain:      true,
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: HealthStatusFail,
+			wantFailed: map[string]string{ShutdownCheck: "server is shutting down"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(zaptest.NewLogger(t),
+				HealthCheck{Name: "database", Check: ok},
+				HealthCheck{Name: "migrations", Check: tt.migrations},
+			)
+			if tt.drain {
+				h.Drain()
+			}
+
+			code, resp := serveHealth(t, h.GetReady, "/readyz")
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			require.Len(t, resp.Checks, 3)
+			for name, check := range resp.Checks {
+				if msg, failed := tt.wantFailed[name]; failed {
+					assert.Equal(t, HealthStatusFail, check.Status, name)
+					assert.Equal(t, msg, check.Error, name)
+					continue
+				}
+				assert.Equal(t, HealthStatusOK, check.Status, name)
+				assert.Empty(t, check.Error, name)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_GetReadyTimeout(t *testing.T) {
+	h := NewHealthHandler(zaptest.NewLogger(t), HealthCheck{
+		Name: "database",
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h.GetReady(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"database":{"status":"fail","error":"context canceled"`)
+}
@@ -0,0 +1,232 @@
+package rest
+
+import (
+	"cart-api/internal/model"
+	"cart-api/internal/services"
+	"cart-api/internal/transport/dto"
+	"cart-api/internal/transport/problem"
+	"cart-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type CatalogProvider interface {
+	ListProducts(context.Context) ([]model.Product, error)
+	GetProduct(context.Context, string) (*model.Product, error)
+	CreateProduct(context.Context, model.Product) (*model.Product, error)
+	UpdateProduct(context.Context, model.Product) (*model.Product, error)
+	DeleteProduct(context.Context, string) error
+	GetStock(context.Context, string) (*model.Stock, error)
+	SetStock(context.Context, string, int) (*model.Stock, error)
+}
+
+type ProductHandler struct {
+	service CatalogProvider
+	logger  *zap.Logger
+}
+
+func NewProductHandler(service CatalogProvider, l *zap.Logger) *ProductHandler {
+	return &ProductHandler{
+		service,
+		l,
+	}
+}
+
+func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+	products, err := h.service.ListProducts(ctx)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	resp := make([]dto.ProductResponse, 0, len(products))
+	for _, product := range products {
+		resp = append(resp, toProductResponse(product))
+	}
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		h.log(ctx).Error("error encoding products", zap.Error(err))
+		return
+	}
+}
+
+func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+	sku := r.PathValue("sku")
+	product, err := h.service.GetProduct(ctx, sku)
+	if err != nil {
+		h.writeError(w, r, err, zap.String("sku", sku))
+		return
+	}
+	if err = json.NewEncoder(w).Encode(toProductResponse(*product)); err != nil {
+		h.log(ctx).Error("error encoding product", zap.Error(err))
+		return
+	}
+}
+
+func (h *ProductHandler) PostProduct(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+	var req dto.ProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(ctx).Error("invalid request body", zap.Error(err))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "request body must be valid JSON")
+		return
+	}
+	input, err := toProductModel(req)
+	if err != nil {
+		h.writeError(w, r, err, zap.String("sku", req.SKU))
+		return
+	}
+	product, err := h.service.CreateProduct(ctx, input)
+	if err != nil {
+		h.writeError(w, r, err, zap.String("sku", req.SKU))
+		return
+	}
+	h.log(ctx).Info("product created", zap.String("sku", product.SKU))
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(toProductResponse(*product)); err != nil {
+		h.log(ctx).Error("error encoding product", zap.Error(err))
+		return
+	}
+}
+
+func (h *ProductHandler) PutProduct(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+	var req dto.ProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(ctx).Error("invalid request body", zap.Error(err))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "request body must be valid JSON")
+		return
+	}
+	req.SKU = r.PathValue("sku")
+	input, err := toProductModel(req)
+	if err != nil {
+		h.writeError(w, r, err, zap.String("sku", req.SKU))
+		return
+	}
+	product, err := h.service.UpdateProduct(ctx, input)
+	if err != nil {
+		h.writeError(w, r, err, zap.String("sku", req.SKU))
+		return
+	}
+	h.log(ctx).Info("product updated", zap.String("sku", product.SKU))
+	if err = json.NewEncoder(w).Encode(toProductResponse(*product)); err != nil {
+		h.log(ctx).Error("error encoding product", zap.Error(err))
+		return
+	}
+}
+
+func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+	sku := r.PathValue("sku")
+	if err := h.service.DeleteProduct(ctx, sku); err != nil {
+		h.writeError(w, r, err, zap.String("sku", sku))
+		return
+	}
+	h.log(ctx).Info("product deleted", zap.String("sku", sku))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProductHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+	sku := r.PathValue("sku")
+	stock, err := h.service.GetStock(ctx, sku)
+	if err != nil {
+		h.writeError(w, r, err, zap.String("sku", sku))
+		return
+	}
+	if err = json.NewEncoder(w).Encode(toStockResponse(*stock)); err != nil {
+		h.log(ctx).Error("error encoding stock", zap.Error(err))
+		return
+	}
+}
+
+func (h *ProductHandler) PutStock(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+	sku := r.PathValue("sku")
+	var req dto.StockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(ctx).Error("invalid request body", zap.Error(err))
+		problem.Error(w, r, http.StatusBadRequest, problem.CodeInvalidBody, "request body must be valid JSON")
+		return
+	}
+	stock, err := h.service.SetStock(ctx, sku, req.OnHand)
+	if err != nil {
+		h.writeError(w, r, err, zap.String("sku", sku))
+		return
+	}
+	h.log(ctx).Info("stock updated", zap.String("sku", sku), zap.Int("on_hand", stock.OnHand))
+	if err = json.NewEncoder(w).Encode(toStockResponse(*stock)); err != nil {
+		h.log(ctx).Error("error encoding stock", zap.Error(err))
+		return
+	}
+}
+
+func (h *ProductHandler) log(ctx context.Context) *zap.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
+
+func (h *ProductHandler) writeError(w http.ResponseWriter, r *http.Request, err error, fields ...zap.Field) {
+	writeError(w, r, h.log(r.Context()), err, fields...)
+}
+
+// toProductModel reads the price, decoded in hundredths, in the minor units
+// of the product currency.
+func toProductModel(req dto.ProductRequest) (model.Product, error) {
+	if !req.Price.Fits(req.Currency) {
+		return model.Product{}, fmt.Errorf("%w: %s has no decimals", services.ErrInvalidPrice, req.Currency)
+	}
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	return model.Product{
+		SKU:         req.SKU,
+		Name:        req.Name,
+		Price:       req.Price.In(req.Currency),
+		TaxCategory: req.TaxCategory,
+		Dimensions: model.Dimensions{
+			WeightGrams: req.WeightGrams,
+			LengthMM:    req.LengthMM,
+			WidthMM:     req.WidthMM,
+			HeightMM:    req.HeightMM,
+		},
+		Active: active,
+	}, nil
+}
+
+func toProductResponse(product model.Product) dto.ProductResponse {
+	return dto.ProductResponse{
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Price:       product.Price,
+		Currency:    product.Price.Currency,
+		TaxCategory: product.TaxCategory,
+		WeightGrams: product.Dimensions.WeightGrams,
+		LengthMM:    product.Dimensions.LengthMM,
+		WidthMM:     product.Dimensions.WidthMM,
+		HeightMM:    product.Dimensions.HeightMM,
+		Active:      product.Active,
+		CreatedAt:   product.CreatedAt,
+		UpdatedAt:   product.UpdatedAt,
+	}
+}
+
+func toStockResponse(stock model.Stock) dto.StockResponse {
+	return dto.StockResponse{
+		SKU:       stock.SKU,
+		OnHand:    stock.OnHand,
+		Reserved:  stock.Reserved,
+		Available: stock.Available(),
+	}
+}
@@ -0,0 +1,206 @@
+package rest
+
+import (
+	"bytes"
+	"cart-api/internal/model"
+	"cart-api/internal/repository/Product"
+	"cart-api/internal/services"
+	"cart-api/pkg/money"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+type MockCatalog struct {
+	mock.Mock
+}
+
+func (m *MockCatalog) ListProducts(ctx context.Context) ([]model.Product, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Product), args.Error(1)
+}
+
+func (m *MockCatalog) GetProduct(ctx context.Context, sku string) (*model.Product, error) {
+	args := m.Called(ctx, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockCatalog) CreateProduct(ctx context.Context, product model.Product) (*model.Product, error) {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockCatalog) UpdateProduct(ctx context.Context, product model.Product) (*model.Product, error) {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *MockCatalog) DeleteProduct(ctx context.Context, sku string) error {
+	return m.Called(ctx, sku).Error(0)
+}
+
+func (m *MockCatalog) GetStock(ctx context.Context, sku string) (*model.Stock, error) {
+	args := m.Called(ctx, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Stock), args.Error(1)
+}
+
+func (m *MockCatalog) SetStock(ctx context.Context, sku string, onHand int) (*model.Stock, error) {
+	args := m.Called(ctx, sku, onHand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Stock), args.Error(1)
+}
+
+func TestProductHandler(t *testing.T) {
+	shoes := &model.Product{SKU: "SHO-1", Name: "Shoes", Price: usd("100.00"), Active: true}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMock      func(*MockCatalog)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			path:   "/products",
+			setupMock: func(m *MockCatalog) {
+				m.On("ListProducts", mock.Anything).Return([]model.Product{*shoes}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"sku":"SHO-1"`,
+		},
+		{
+			name:   "Get Not Found",
+			method: http.MethodGet,
+			path:   "/products/NOPE",
+			setupMock: func(m *MockCatalog) {
+				m.On("GetProduct", mock.Anything, "NOPE").Return(nil, services.ErrProductNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/products",
+			body:   `{"sku": "SHO-1", "name": "Shoes", "price": 100, "currency": "USD"}`,
+			setupMock: func(m *MockCatalog) {
+				m.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p model.Product) bool {
+					return p.SKU == "SHO-1" && p.Active && p.Price.String() == "100.00"
+				})).Return(shoes, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"price":100.00`,
+		},
+		{
+			name:   "Create Zero Decimal Currency",
+			method: http.MethodPost,
+			path:   "/products",
+			body:   `{"sku": "TEA-1", "name": "Tea", "price": 1500, "currency": "JPY"}`,
+			setupMock: func(m *MockCatalog) {
+				m.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p model.Product) bool {
+					return p.Price == money.MustParse("1500", "JPY")
+				})).Return(&model.Product{SKU: "TEA-1", Name: "Tea", Price: money.MustParse("1500", "JPY")}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"price":1500,"currency":"JPY"`,
+		},
+		{
+			name:           "Create Yen Fraction",
+			method:         http.MethodPost,
+			path:           "/products",
+			body:           `{"sku": "TEA-1", "name": "Tea", "price": 1500.5, "currency": "JPY"}`,
+			setupMock:      func(*MockCatalog) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"validation_failed"`,
+		},
+		{
+			name:   "Create Duplicate",
+			method: http.MethodPost,
+			path:   "/products",
+			body:   `{"sku": "SHO-1", "name": "Shoes", "price": 100}`,
+			setupMock: func(m *MockCatalog) {
+				m.On("CreateProduct", mock.Anything, mock.Anything).Return(nil, Product.ErrAlreadyExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Update Deactivates",
+			method: http.MethodPut,
+			path:   "/products/SHO-1",
+			body:   `{"name": "Shoes", "price": 100, "active": false}`,
+			setupMock: func(m *MockCatalog) {
+				m.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(p model.Product) bool {
+					return p.SKU == "SHO-1" && !p.Active
+				})).Return(shoes, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Set Stock",
+			method: http.MethodPut,
+			path:   "/products/SHO-1/stock",
+			body:   `{"on_hand": 10}`,
+			setupMock: func(m *MockCatalog) {
+				m.On("SetStock", mock.Anything, "SHO-1", 10).Return(&model.Stock{SKU: "SHO-1", OnHand: 10, Reserved: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"available":7`,
+		},
+		{
+			name:   "Delete Not Admin",
+			method: http.MethodDelete,
+			path:   "/products/SHO-1",
+			setupMock: func(m *MockCatalog) {
+				m.On("DeleteProduct", mock.Anything, "SHO-1").Return(services.ErrAdminRequired)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCatalog := new(MockCatalog)
+			tt.setupMock(mockCatalog)
+			handler := NewProductHandler(mockCatalog, zaptest.NewLogger(t))
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /products", handler.GetProducts)
+			mux.HandleFunc("GET /products/{sku}", handler.GetProduct)
+			mux.HandleFunc("POST /products", handler.PostProduct)
+			mux.HandleFunc("PUT /products/{sku}", handler.PutProduct)
+			mux.HandleFunc("DELETE /products/{sku}", handler.DeleteProduct)
+			mux.HandleFunc("PUT /products/{sku}/stock", handler.PutStock)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockCatalog.AssertExpectations(t)
+		})
+	}
+}
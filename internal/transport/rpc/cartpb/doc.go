@@ -0,0 +1,4 @@
+// Package cartpb holds the messages and gRPC stubs generated from cart.proto.
+package cartpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cart.proto
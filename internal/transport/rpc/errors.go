@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"cart-api/internal/transport/problem"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo detail attached to every error.
+const ErrorDomain = "cart-api"
+
+// statusCodes maps the problem codes of the REST API onto gRPC codes, so both
+// transports classify a service error the same way. Codes missing here
+// become codes.Internal.
+var statusCodes = map[string]codes.Code{
+	problem.CodeInvalidCartID:       codes.InvalidArgument,
+	problem.CodeInvalidItemID:       codes.InvalidArgument,
+	problem.CodeInvalidBody:         codes.InvalidArgument,
+	problem.CodeValidation:          codes.InvalidArgument,
+	problem.CodeCouponRejected:      codes.InvalidArgument,
+	problem.CodeMergeRejected:       codes.InvalidArgument,
+	problem.CodeUnknownExchangeRate: codes.InvalidArgument,
+	problem.CodeUnknownTaxRegion:    codes.InvalidArgument,
+	problem.CodeShippingUnavailable: codes.InvalidArgument,
+	problem.CodeUnauthenticated:     codes.Unauthenticated,
+	problem.CodeForbidden:           codes.PermissionDenied,
+	problem.CodeAdminRequired:       codes.PermissionDenied,
+	problem.CodeCartNotFound:        codes.NotFound,
+	problem.CodeItemNotFound:        codes.NotFound,
+	problem.CodeProductNotFound:     codes.NotFound,
+	problem.CodeCouponNotFound:      codes.NotFound,
+	problem.CodeProductExists:       codes.AlreadyExists,
+	problem.CodeCouponConflict:      codes.FailedPrecondition,
+	problem.CodeCartLimit:           codes.FailedPrecondition,
+	problem.CodeInsufficientStock:   codes.FailedPrecondition,
+	problem.CodeCartNotOpen:         codes.FailedPrecondition,
+	problem.CodeCartEmpty:           codes.FailedPrecondition,
+	problem.CodeCheckoutNotStarted:  codes.FailedPrecondition,
+	problem.CodeCurrencyMismatch:    codes.FailedPrecondition,
+	problem.CodeVersionMismatch:     codes.Aborted,
+	problem.CodeInternal:            codes.Internal,
+}
+
+// statusFor translates a service error into a gRPC status. The REST problem
+// code and its extension members travel as a google.rpc.ErrorInfo detail.
+func statusFor(err error) *status.Status {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	}
+	p := problem.For(err)
+	code, ok := statusCodes[p.Code]
+	if !ok {
+		code = codes.Internal
+	}
+	return newStatus(code, p.Code, p.Detail, p.Extensions)
+}
+
+func newStatus(code codes.Code, reason, message string, extensions map[string]any) *status.Status {
+	st := status.New(code, message)
+	info := &errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain}
+	if len(extensions) > 0 {
+		info.Metadata = make(map[string]string, len(extensions))
+		for key, value := range extensions {
+			info.Metadata[key] = fmt.Sprint(value)
+		}
+	}
+	if detailed, err := st.WithDetails(info); err == nil {
+		return detailed
+	}
+	return st
+}
+
+func invalidArgument(reason, message string) error {
+	return newStatus(codes.InvalidArgument, reason, message, nil).Err()
+}
+
+// serviceError logs err and returns its status. Client errors are logged as
+// warnings, everything else as errors.
+func serviceError(logger *zap.Logger, err error, fields ...zap.Field) error {
+	st := statusFor(err)
+	fields = append(fields, zap.Error(err), zap.String("grpc_code", st.Code().String()))
+	switch st.Code() {
+	case codes.Internal, codes.Unknown, codes.DeadlineExceeded:
+		logger.Error("rpc failed", fields...)
+	default:
+		logger.Warn("rpc rejected", fields...)
+	}
+	return st.Err()
+}
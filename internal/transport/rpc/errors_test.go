@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"cart-api/internal/repository/Product"
+	"cart-api/internal/services"
+	"cart-api/internal/transport/problem"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func errorInfo(t *testing.T, st *status.Status) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	require.Fail(t, "status has no ErrorInfo detail", st.String())
+	return nil
+}
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"Cart Not Found", fmt.Errorf("lock: %w", services.ErrCartNotFound), codes.NotFound, problem.CodeCartNotFound},
+		{"Unauthenticated", services.ErrUnauthenticated, codes.Unauthenticated, problem.CodeUnauthenticated},
+		{"Forbidden", services.ErrForbidden, codes.PermissionDenied, problem.CodeForbidden},
+		{"Invalid Quantity", services.ErrInvalidQuantity, codes.InvalidArgument, problem.CodeValidation},
+		{"Product Exists", Product.ErrAlreadyExists, codes.AlreadyExists, problem.CodeProductExists},
+		{"Cart Not Open", services.ErrCartNotOpen, codes.FailedPrecondition, problem.CodeCartNotOpen},
+		{"Cart Limit", &services.LimitError{Limit: services.LimitUnits, Max: "20", Actual: "21"}, codes.FailedPrecondition, problem.CodeCartLimit},
+		{"Version Mismatch", services.ErrVersionMismatch, codes.Aborted, problem.CodeVersionMismatch},
+		{"Unknown", errors.New("connection reset"), codes.Internal, problem.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := statusFor(tt.err)
+
+			assert.Equal(t, tt.code, st.Code())
+			info := errorInfo(t, st)
+			assert.Equal(t, tt.reason, info.GetReason())
+			assert.Equal(t, ErrorDomain, info.GetDomain())
+		})
+	}
+
+	t.Run("Context Errors", func(t *testing.T) {
+		assert.Equal(t, codes.DeadlineExceeded, statusFor(fmt.Errorf("query: %w", context.DeadlineExceeded)).Code())
+		assert.Equal(t, codes.Canceled, statusFor(context.Canceled).Code())
+	})
+
+	t.Run("Internal Message Hides Cause", func(t *testing.T) {
+		st := statusFor(errors.New("pq: password authentication failed"))
+
+		assert.NotContains(t, st.Message(), "password")
+	})
+
+	t.Run("Stock Metadata", func(t *testing.T) {
+		st := statusFor(&services.InsufficientStockError{SKU: "APL-1", Requested: 3, Available: 2})
+
+		assert.Equal(t, codes.FailedPrecondition, st.Code())
+		assert.Equal(t, map[string]string{"sku": "APL-1", "requested": "3", "available": "2"}, errorInfo(t, st).GetMetadata())
+	})
+}
@@ -0,0 +1,92 @@
+package rpc
+
+import (
+	"cart-api/internal/auth"
+	"cart-api/internal/transport/middleware"
+	"cart-api/internal/transport/problem"
+	"cart-api/internal/transport/requestid"
+	"cart-api/pkg/logger"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+// requestIDKey is the metadata key of the request id; gRPC lowercases keys.
+var requestIDKey = strings.ToLower(requestid.Header)
+
+// AccessLog propagates the caller's x-request-id or generates one, stores a
+// request scoped logger in the context and logs every call once it completes.
+func AccessLog(base *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		id := firstMetadata(ctx, requestIDKey)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+		l := base.With(zap.String("request_id", id))
+		ctx = logger.WithContext(requestid.NewContext(ctx, id), l)
+
+		resp, err := handler(ctx, req)
+		l.Info("grpc request",
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("latency", time.Since(start)),
+		)
+		return resp, err
+	}
+}
+
+// Recover turns a handler panic into a logged INTERNAL error instead of a
+// crashed process.
+func Recover(base *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			logger.FromContext(ctx, base).Error("handler panicked",
+				zap.String("panic", fmt.Sprint(rec)),
+				zap.String("method", info.FullMethod),
+				zap.Stack("stack"),
+			)
+			err = newStatus(codes.Internal, problem.CodeInternal, "The request could not be processed", nil).Err()
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// Authenticate verifies the bearer token of the "authorization" metadata, the
+// same way middleware.Authenticate does for HTTP.
+func Authenticate(verifier middleware.Verifier, base *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		token, ok := strings.CutPrefix(firstMetadata(ctx, "authorization"), "Bearer ")
+		if !ok {
+			token = ""
+		}
+		principal, err := verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			logger.FromContext(ctx, base).Warn("authentication failed",
+				zap.Error(err),
+				zap.String("method", info.FullMethod),
+			)
+			return nil, newStatus(codes.Unauthenticated, problem.CodeUnauthenticated, "a valid bearer token is required", nil).Err()
+		}
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+package rpc
+
+import (
+	"cart-api/internal/exchange"
+	"cart-api/internal/model"
+	"cart-api/internal/pricing"
+	"cart-api/internal/services"
+	"cart-api/internal/transport/middleware"
+	"cart-api/internal/transport/problem"
+	"cart-api/internal/transport/rpc/cartpb"
+	"cart-api/pkg/logger"
+	"cart-api/pkg/money"
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// CartProvider is the part of the cart service the gRPC API exposes.
+type CartProvider interface {
+	CreateCart(context.Context) (*model.Cart, error)
+	CreateItem(context.Context, model.CartItem) (*model.CartItem, error)
+	DeleteItem(context.Context, model.CartItem) error
+	GetCart(context.Context, int) (*model.Cart, error)
+	GetPrice(context.Context, int) (*model.Price, error)
+}
+
+// CartServer implements cartpb.CartServiceServer on top of the same service
+// as the REST handlers.
+type CartServer struct {
+	cartpb.UnimplementedCartServiceServer
+	service CartProvider
+	logger  *zap.Logger
+}
+
+func NewCartServer(service CartProvider, l *zap.Logger) *CartServer {
+	return &CartServer{
+		service: service,
+		logger:  l,
+	}
+}
+
+// NewServer returns a gRPC server with CartService registered behind the
+// request logging, panic recovery and authentication interceptors.
+func NewServer(cart *CartServer, verifier middleware.Verifier, base *zap.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		AccessLog(base),
+		Recover(base),
+		Authenticate(verifier, base),
+	))
+	server := grpc.NewServer(opts...)
+	cartpb.RegisterCartServiceServer(server, cart)
+	return server
+}
+
+func (s *CartServer) CreateCart(ctx context.Context, _ *cartpb.CreateCartRequest) (*cartpb.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	cart, err := s.service.CreateCart(ctx)
+	if err != nil {
+		return nil, serviceError(s.log(ctx), err)
+	}
+	return toCart(*cart), nil
+}
+
+func (s *CartServer) AddItem(ctx context.Context, req *cartpb.AddItemRequest) (*cartpb.Item, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	if req.GetCartId() <= 0 {
+		return nil, invalidArgument(problem.CodeInvalidCartID, "cart_id must be a positive integer")
+	}
+	ctx = withExpectedVersion(ctx, req.GetExpectedVersion())
+	item, err := s.service.CreateItem(ctx, model.CartItem{
+		CartId:   int(req.GetCartId()),
+		SKU:      req.GetSku(),
+		Quantity: int(req.GetQuantity()),
+	})
+	if err != nil {
+		return nil, serviceError(s.log(ctx), err, zap.Int64("cart_id", req.GetCartId()), zap.String("sku", req.GetSku()))
+	}
+	return toItem(*item), nil
+}
+
+func (s *CartServer) RemoveItem(ctx context.Context, req *cartpb.RemoveItemRequest) (*cartpb.RemoveItemResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	if req.GetCartId() <= 0 {
+		return nil, invalidArgument(problem.CodeInvalidCartID, "cart_id must be a positive integer")
+	}
+	if req.GetItemId() <= 0 {
+		return nil, invalidArgument(problem.CodeInvalidItemID, "item_id must be a positive integer")
+	}
+	ctx = withExpectedVersion(ctx, req.GetExpectedVersion())
+	err := s.service.DeleteItem(ctx, model.CartItem{Id: int(req.GetItemId()), CartId: int(req.GetCartId())})
+	if err != nil {
+		return nil, serviceError(s.log(ctx), err, zap.Int64("cart_id", req.GetCartId()), zap.Int64("item_id", req.GetItemId()))
+	}
+	s.log(ctx).Info("item deleted successfully",
+		zap.Int64("cart_id", req.GetCartId()),
+		zap.Int64("item_id", req.GetItemId()),
+	)
+	return &cartpb.RemoveItemResponse{}, nil
+}
+
+func (s *CartServer) GetCart(ctx context.Context, req *cartpb.GetCartRequest) (*cartpb.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	if req.GetCartId() <= 0 {
+		return nil, invalidArgument(problem.CodeInvalidCartID, "cart_id must be a positive integer")
+	}
+	cart, err := s.service.GetCart(ctx, int(req.GetCartId()))
+	if err != nil {
+		return nil, serviceError(s.log(ctx), err, zap.Int64("cart_id", req.GetCartId()))
+	}
+	return toCart(*cart), nil
+}
+
+func (s *CartServer) GetPrice(ctx context.Context, req *cartpb.GetPriceRequest) (*cartpb.Price, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	if req.GetCartId() <= 0 {
+		return nil, invalidArgument(problem.CodeInvalidCartID, "cart_id must be a positive integer")
+	}
+	if region := req.GetRegion(); region != "" {
+		ctx = services.WithTaxRegion(ctx, region)
+	}
+	if currency := req.GetCurrency(); currency != "" {
+		ctx = services.WithDisplayCurrency(ctx, currency)
+	}
+	price, err := s.service.GetPrice(ctx, int(req.GetCartId()))
+	if err != nil {
+		return nil, serviceError(s.log(ctx), err, zap.Int64("cart_id", req.GetCartId()))
+	}
+	return toPrice(*price), nil
+}
+
+func (s *CartServer) log(ctx context.Context) *zap.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
+
+func withExpectedVersion(ctx context.Context, version int64) context.Context {
+	if version <= 0 {
+		return ctx
+	}
+	return services.WithExpectedVersion(ctx, int(version))
+}
+
+func toMoney(m money.Money) *cartpb.Money {
+	return &cartpb.Money{Amount: m.String(), Currency: m.Currency}
+}
+
+func toItem(item model.CartItem) *cartpb.Item {
+	return &cartpb.Item{
+		Id:       int64(item.Id),
+		CartId:   int64(item.CartId),
+		Sku:      item.SKU,
+		Product:  item.Product,
+		Price:    toMoney(item.Price),
+		Quantity: int64(item.Quantity),
+	}
+}
+
+func toCart(cart model.Cart) *cartpb.Cart {
+	resp := &cartpb.Cart{
+		Id:       int64(cart.ID),
+		Currency: cart.Currency,
+		Version:  int64(cart.Version),
+		Items:    make([]*cartpb.Item, 0, len(cart.Items)),
+	}
+	for _, item := range cart.Items {
+		resp.Items = append(resp.Items, toItem(item))
+	}
+	return resp
+}
+
+func toPrice(price model.Price) *cartpb.Price {
+	resp := &cartpb.Price{
+		CartId:          int64(price.CartId),
+		Currency:        price.TotalPrice.Currency,
+		TotalPrice:      toMoney(price.TotalPrice),
+		DiscountPercent: int64(price.DiscountPercent),
+		DiscountAmount:  toMoney(price.DiscountAmount),
+		Discounts:       make([]*cartpb.AppliedDiscount, 0, len(price.Discounts)),
+		Coupons:         make([]*cartpb.AppliedCoupon, 0, len(price.Coupons)),
+		FinalPrice:      toMoney(price.FinalPrice),
+		Region:          price.Region,
+		TaxMode:         price.TaxMode,
+		Subtotal:        toMoney(price.Subtotal),
+		Taxes:           make([]*cartpb.TaxLine, 0, len(price.Taxes)),
+		TaxTotal:        toMoney(price.TaxTotal),
+		GrandTotal:      toMoney(price.GrandTotal),
+	}
+	for _, discount := range price.Discounts {
+		resp.Discounts = append(resp.Discounts, &cartpb.AppliedDiscount{
+			Rule:   discount.Rule,
+			Amount: toMoney(discount.Amount),
+		})
+	}
+	for _, coupon := range price.Coupons {
+		resp.Coupons = append(resp.Coupons, &cartpb.AppliedCoupon{
+			Code:   coupon.Code,
+			Amount: toMoney(coupon.Amount),
+		})
+	}
+	if price.Shipping != nil {
+		resp.Shipping = &cartpb.AppliedShipping{
+			Method: price.Shipping.Method,
+			Amount: toMoney(price.Shipping.Amount),
+		}
+	}
+	if price.Exchange != nil {
+		resp.Exchange = &cartpb.ExchangeRate{
+			From:      price.Exchange.From,
+			To:        price.Exchange.To,
+			Rate:      exchange.FormatRate(price.Exchange.Rate),
+			UpdatedAt: timestamppb.New(price.Exchange.UpdatedAt),
+		}
+	}
+	for _, tax := range price.Taxes {
+		resp.Taxes = append(resp.Taxes, &cartpb.TaxLine{
+			Category: tax.Category,
+			Rate:     pricing.FormatRate(tax.Rate),
+			Taxable:  toMoney(tax.Taxable),
+			Amount:   toMoney(tax.Amount),
+		})
+	}
+	return resp
+}
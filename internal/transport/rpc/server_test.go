@@ -0,0 +1,228 @@
+package rpc
+
+import (
+	"cart-api/internal/auth"
+	"cart-api/internal/model"
+	"cart-api/internal/services"
+	"cart-api/internal/transport/problem"
+	"cart-api/internal/transport/rpc/cartpb"
+	"cart-api/pkg/money"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type MockCartService struct {
+	mock.Mock
+}
+
+func (m *MockCartService) CreateCart(ctx context.Context) (*model.Cart, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Cart), args.Error(1)
+}
+
+func (m *MockCartService) CreateItem(ctx context.Context, item model.CartItem) (*model.CartItem, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CartItem), args.Error(1)
+}
+
+func (m *MockCartService) DeleteItem(ctx context.Context, item model.CartItem) error {
+	return m.Called(ctx, item).Error(0)
+}
+
+func (m *MockCartService) GetCart(ctx context.Context, id int) (*model.Cart, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Cart), args.Error(1)
+}
+
+func (m *MockCartService) GetPrice(ctx context.Context, id int) (*model.Price, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Price), args.Error(1)
+}
+
+type stubVerifier struct{}
+
+func (stubVerifier) Verify(token string) (auth.Principal, error) {
+	if token != "valid" {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+	return auth.Principal{UserID: "user-1"}, nil
+}
+
+func newTestClient(t *testing.T, service CartProvider) cartpb.CartServiceClient {
+	t.Helper()
+	logger := zaptest.NewLogger(t)
+	server := NewServer(NewCartServer(service, logger), stubVerifier{}, logger)
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return cartpb.NewCartServiceClient(conn)
+}
+
+func authorized() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer valid")
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status error: %v", err)
+	assert.Equal(t, code, st.Code())
+	assert.Equal(t, reason, errorInfo(t, st).GetReason())
+}
+
+func TestCartServer_Authentication(t *testing.T) {
+	client := newTestClient(t, &MockCartService{})
+
+	_, err := client.GetCart(context.Background(), &cartpb.GetCartRequest{CartId: 1})
+	requireStatus(t, err, codes.Unauthenticated, problem.CodeUnauthenticated)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer forged")
+	_, err = client.GetCart(ctx, &cartpb.GetCartRequest{CartId: 1})
+	requireStatus(t, err, codes.Unauthenticated, problem.CodeUnauthenticated)
+}
+
+func TestCartServer_GetCart(t *testing.T) {
+	service := &MockCartService{}
+	service.On("GetCart", mock.MatchedBy(func(ctx context.Context) bool {
+		principal, ok := auth.FromContext(ctx)
+		return ok && principal.UserID == "user-1"
+	}), 7).Return(&model.Cart{
+		ID:       7,
+		Currency: "EUR",
+		Version:  3,
+		Items: []model.CartItem{
+			{Id: 1, CartId: 7, SKU: "APL-1", Product: "Apple", Price: money.MustParse("1.50", "EUR"), Quantity: 2},
+		},
+	}, nil)
+	client := newTestClient(t, service)
+
+	var header metadata.MD
+	cart, err := client.GetCart(authorized(), &cartpb.GetCartRequest{CartId: 7}, grpc.Header(&header))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), cart.GetId())
+	assert.Equal(t, int64(3), cart.GetVersion())
+	require.Len(t, cart.GetItems(), 1)
+	assert.Equal(t, "APL-1", cart.GetItems()[0].GetSku())
+	assert.Equal(t, "1.50", cart.GetItems()[0].GetPrice().GetAmount())
+	assert.Equal(t, "EUR", cart.GetItems()[0].GetPrice().GetCurrency())
+	assert.Len(t, header.Get("x-request-id"), 1)
+	service.AssertExpectations(t)
+}
+
+func TestCartServer_AddItem(t *testing.T) {
+	t.Run("Expected Version", func(t *testing.T) {
+		service := &MockCartService{}
+		service.On("CreateItem", mock.MatchedBy(func(ctx context.Context) bool {
+			version, ok := services.ExpectedVersion(ctx)
+			return ok && version == 4
+		}), model.CartItem{CartId: 7, SKU: "APL-1", Quantity: 2}).
+			Return(&model.CartItem{Id: 9, CartId: 7, SKU: "APL-1", Product: "Apple", Quantity: 2}, nil)
+		client := newTestClient(t, service)
+
+		item, err := client.AddItem(authorized(), &cartpb.AddItemRequest{CartId: 7, Sku: "APL-1", Quantity: 2, ExpectedVersion: 4})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), item.GetId())
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Insufficient Stock", func(t *testing.T) {
+		service := &MockCartService{}
+		service.On("CreateItem", mock.Anything, mock.Anything).
+			Return(nil, &services.InsufficientStockError{SKU: "APL-1", Requested: 5, Available: 1})
+		client := newTestClient(t, service)
+
+		_, err := client.AddItem(authorized(), &cartpb.AddItemRequest{CartId: 7, Sku: "APL-1", Quantity: 5})
+
+		requireStatus(t, err, codes.FailedPrecondition, problem.CodeInsufficientStock)
+	})
+
+	t.Run("Invalid Cart ID", func(t *testing.T) {
+		client := newTestClient(t, &MockCartService{})
+
+		_, err := client.AddItem(authorized(), &cartpb.AddItemRequest{Sku: "APL-1", Quantity: 1})
+
+		requireStatus(t, err, codes.InvalidArgument, problem.CodeInvalidCartID)
+	})
+}
+
+func TestCartServer_RemoveItem(t *testing.T) {
+	service := &MockCartService{}
+	service.On("DeleteItem", mock.Anything, model.CartItem{Id: 9, CartId: 7}).Return(services.ErrVersionMismatch)
+	client := newTestClient(t, service)
+
+	_, err := client.RemoveItem(authorized(), &cartpb.RemoveItemRequest{CartId: 7, ItemId: 9, ExpectedVersion: 2})
+
+	requireStatus(t, err, codes.Aborted, problem.CodeVersionMismatch)
+}
+
+func TestCartServer_GetPrice(t *testing.T) {
+	service := &MockCartService{}
+	service.On("GetPrice", mock.Anything, 7).Return(&model.Price{
+		CartId:     7,
+		TotalPrice: money.MustParse("10.00", "EUR"),
+		FinalPrice: money.MustParse("9.00", "EUR"),
+		GrandTotal: money.MustParse("10.80", "EUR"),
+		Discounts:  []model.AppliedDiscount{{Rule: "SPRING", Amount: money.MustParse("1.00", "EUR")}},
+		Shipping:   &model.AppliedShipping{Method: "standard", Amount: money.MustParse("0.00", "EUR")},
+	}, nil)
+	client := newTestClient(t, service)
+
+	price, err := client.GetPrice(authorized(), &cartpb.GetPriceRequest{CartId: 7})
+
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", price.GetCurrency())
+	assert.Equal(t, "9.00", price.GetFinalPrice().GetAmount())
+	assert.Equal(t, "10.80", price.GetGrandTotal().GetAmount())
+	require.Len(t, price.GetDiscounts(), 1)
+	assert.Equal(t, "SPRING", price.GetDiscounts()[0].GetRule())
+	assert.Equal(t, "standard", price.GetShipping().GetMethod())
+	assert.Nil(t, price.GetExchange())
+}
+
+func TestCartServer_Recover(t *testing.T) {
+	service := &MockCartService{}
+	service.On("CreateCart", mock.Anything).Run(func(mock.Arguments) {
+		panic("boom")
+	}).Return(nil, errors.New("unreachable"))
+	client := newTestClient(t, service)
+
+	_, err := client.CreateCart(authorized(), &cartpb.CreateCartRequest{})
+
+	requireStatus(t, err, codes.Internal, problem.CodeInternal)
+}
@@ -0,0 +1,137 @@
+package worker
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CartSweepRepository interface {
+	MarkAbandoned(ctx context.Context, idleSince time.Time, limit int) (int64, error)
+	DeleteExpired(ctx context.Context, idleSince time.Time, limit int) (int64, error)
+}
+
+type KeyPurger interface {
+	PurgeExpired(ctx context.Context, limit int) (int64, error)
+}
+
+type ReservationPurger interface {
+	PurgeExpired(ctx context.Context, limit int) (int64, error)
+}
+
+type SweeperConfig struct {
+	AbandonAfter time.Duration
+	TTL          time.Duration
+	Interval     time.Duration
+	BatchSize    int
+}
+
+// Sweeper periodically flags carts that have been idle for AbandonAfter as
+// abandoned and deletes carts that have been idle for longer than TTL.
+type Sweeper struct {
+	repo         CartSweepRepository
+	keys         KeyPurger
+	reservations ReservationPurger
+	cfg          SweeperConfig
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+type SweeperOption func(*Sweeper)
+
+func WithIdempotencyKeys(keys KeyPurger) SweeperOption {
+	return func(s *Sweeper) {
+		s.keys = keys
+	}
+}
+
+func WithReservations(reservations ReservationPurger) SweeperOption {
+	return func(s *Sweeper) {
+		s.reservations = reservations
+	}
+}
+
+func NewSweeper(repo CartSweepRepository, cfg SweeperConfig, logger *zap.Logger, opts ...SweeperOption) *Sweeper {
+	s := &Sweeper{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	s.logger.Info("cart sweeper started",
+		zap.Duration("interval", s.cfg.Interval),
+		zap.Duration("abandon_after", s.cfg.AbandonAfter),
+		zap.Duration("ttl", s.cfg.TTL),
+	)
+	for {
+		s.Sweep(ctx)
+		select {
+		case <-ctx.Done():
+			s.logger.Info("cart sweeper stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sweeper) Sweep(ctx context.Context) {
+	now := s.now()
+	abandoned, err := s.drain(ctx, s.repo.MarkAbandoned, now.Add(-s.cfg.AbandonAfter))
+	if err != nil && ctx.Err() == nil {
+		s.logger.Error("failed to mark abandoned carts", zap.Error(err))
+	}
+	deleted, err := s.drain(ctx, s.repo.DeleteExpired, now.Add(-s.cfg.TTL))
+	if err != nil && ctx.Err() == nil {
+		s.logger.Error("failed to delete expired carts", zap.Error(err))
+	}
+	var purged int64
+	if s.keys != nil {
+		purged, err = s.drain(ctx, func(ctx context.Context, _ time.Time, limit int) (int64, error) {
+			return s.keys.PurgeExpired(ctx, limit)
+		}, now)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to purge expired idempotency keys", zap.Error(err))
+		}
+	}
+	var released int64
+	if s.reservations != nil {
+		released, err = s.drain(ctx, func(ctx context.Context, _ time.Time, limit int) (int64, error) {
+			return s.reservations.PurgeExpired(ctx, limit)
+		}, now)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to purge expired reservations", zap.Error(err))
+		}
+	}
+	if abandoned > 0 || deleted > 0 || purged > 0 || released > 0 {
+		s.logger.Info("cart sweep finished",
+			zap.Int64("abandoned", abandoned),
+			zap.Int64("deleted", deleted),
+			zap.Int64("idempotency_keys_purged", purged),
+			zap.Int64("reservations_released", released),
+		)
+	}
+}
+
+func (s *Sweeper) drain(ctx context.Context, step func(context.Context, time.Time, int) (int64, error), idleSince time.Time) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		n, err := step(ctx, idleSince, s.cfg.BatchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < int64(s.cfg.BatchSize) {
+			return total, nil
+		}
+	}
+	return total, ctx.Err()
+}
@@ -0,0 +1,127 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeSweepRepo struct {
+	mu        sync.Mutex
+	idle      int
+	expired   int
+	abandonAt []time.Time
+	expireAt  []time.Time
+	err       error
+}
+
+func (r *fakeSweepRepo) MarkAbandoned(_ context.Context, idleSince time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.abandonAt = append(r.abandonAt, idleSince)
+	n := min(r.idle, limit)
+	r.idle -= n
+	return int64(n), r.err
+}
+
+func (r *fakeSweepRepo) DeleteExpired(_ context.Context, idleSince time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expireAt = append(r.expireAt, idleSince)
+	n := min(r.expired, limit)
+	r.expired -= n
+	return int64(n), nil
+}
+
+type fakeKeyPurger struct {
+	expired int
+	calls   int
+}
+
+func (p *fakeKeyPurger) PurgeExpired(_ context.Context, limit int) (int64, error) {
+	p.calls++
+	n := min(p.expired, limit)
+	p.expired -= n
+	return int64(n), nil
+}
+
+func newTestSweeper(t *testing.T, repo CartSweepRepository, now time.Time, opts ...SweeperOption) *Sweeper {
+	s := NewSweeper(repo, SweeperConfig{
+		AbandonAfter: time.Hour,
+		TTL:          24 * time.Hour,
+		Interval:     time.Millisecond,
+		BatchSize:    10,
+	}, zaptest.NewLogger(t), opts...)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestSweepBatches(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeSweepRepo{idle: 25, expired: 10}
+
+	newTestSweeper(t, repo, now).Sweep(context.Background())
+
+	assert.Zero(t, repo.idle)
+	assert.Zero(t, repo.expired)
+	assert.Len(t, repo.abandonAt, 3)
+	assert.Len(t, repo.expireAt, 2)
+	assert.Equal(t, now.Add(-time.Hour), repo.abandonAt[0])
+	assert.Equal(t, now.Add(-24*time.Hour), repo.expireAt[0])
+}
+
+func TestSweepPurgesIdempotencyKeys(t *testing.T) {
+	keys := &fakeKeyPurger{expired: 15}
+
+	newTestSweeper(t, &fakeSweepRepo{}, time.Now(), WithIdempotencyKeys(keys)).Sweep(context.Background())
+
+	assert.Zero(t, keys.expired)
+	assert.Equal(t, 2, keys.calls)
+}
+
+func TestSweepReleasesExpiredReservations(t *testing.T) {
+	reservations := &fakeKeyPurger{expired: 4}
+
+	newTestSweeper(t, &fakeSweepRepo{}, time.Now(), WithReservations(reservations)).Sweep(context.Background())
+
+	assert.Zero(t, reservations.expired)
+	assert.Equal(t, 1, reservations.calls)
+}
+
+func TestSweepContinuesAfterError(t *testing.T) {
+	repo := &fakeSweepRepo{idle: 5, expired: 3, err: errors.New("db error")}
+
+	newTestSweeper(t, repo, time.Now()).Sweep(context.Background())
+
+	assert.Len(t, repo.abandonAt, 1)
+	assert.Zero(t, repo.expired)
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	repo := &fakeSweepRepo{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		newTestSweeper(t, repo, time.Now()).Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		return len(repo.abandonAt) > 1
+	}, time.Second, time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop after cancellation")
+	}
+}
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE cart_item ADD COLUMN quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0);
+
+-- Duplicate rows of a product collapse into the oldest one, which keeps its id
+-- and counts them all. The rows may have been added at different prices; the
+-- most recently added one (highest id) wins, as when carts are merged.
+UPDATE cart_item ci
+SET quantity = grouped.cnt, price = latest.price
+FROM (SELECT MIN(id) AS id, MAX(id) AS latest_id, COUNT(*) AS cnt FROM cart_item GROUP BY cart_id, product) grouped
+JOIN cart_item latest ON latest.id = grouped.latest_id
+WHERE ci.id = grouped.id;
+
+DELETE FROM cart_item
+WHERE id NOT IN (SELECT MIN(id) FROM cart_item GROUP BY cart_id, product);
+
+ALTER TABLE cart_item ADD CONSTRAINT cart_item_cart_id_product_key UNIQUE (cart_id, product);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE cart_item DROP CONSTRAINT cart_item_cart_id_product_key;
+ALTER TABLE cart_item DROP COLUMN quantity;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE discount_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    kind VARCHAR(32) NOT NULL,
+    priority INT NOT NULL DEFAULT 100,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    params JSONB NOT NULL DEFAULT '{}'
+);
+
+INSERT INTO discount_rules (name, kind, priority, params) VALUES
+    ('total_over_5000', 'total_threshold', 10, '{"threshold": "5000.00", "percent": 10}'),
+    ('more_than_3_units', 'unit_threshold', 20, '{"threshold": 3, "percent": 5}');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE discount_rules;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE coupons (
+    code VARCHAR(64) PRIMARY KEY,
+    percent INT NOT NULL DEFAULT 0 CHECK (percent BETWEEN 0 AND 100),
+    amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    min_total DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    usage_limit INT,
+    used_count INT NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    CHECK ((percent > 0) <> (amount > 0))
+);
+
+CREATE TABLE cart_coupons (
+    cart_id INT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    code VARCHAR(64) NOT NULL REFERENCES coupons(code) ON DELETE CASCADE,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (cart_id, code)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE cart_coupons, coupons;
+-- +goose StatementEnd
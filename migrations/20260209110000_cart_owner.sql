@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing carts cannot be attributed to anyone, so both columns stay NULL for
+-- them. Rather than leaving such carts unreachable, the service hands a cart
+-- without owner to the first user or session that accesses it.
+ALTER TABLE carts ADD COLUMN user_id VARCHAR(255);
+ALTER TABLE carts ADD COLUMN session_id VARCHAR(255);
+
+CREATE INDEX carts_user_id_idx ON carts (user_id);
+CREATE INDEX carts_session_id_idx ON carts (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX carts_session_id_idx;
+DROP INDEX carts_user_id_idx;
+ALTER TABLE carts DROP COLUMN session_id;
+ALTER TABLE carts DROP COLUMN user_id;
+-- +goose StatementEnd
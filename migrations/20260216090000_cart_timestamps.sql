@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE carts ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE carts ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE carts ADD COLUMN abandoned_at TIMESTAMPTZ;
+ALTER TABLE cart_item ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE cart_item ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX carts_updated_at_idx ON carts (updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX carts_updated_at_idx;
+ALTER TABLE cart_item DROP COLUMN updated_at;
+ALTER TABLE cart_item DROP COLUMN created_at;
+ALTER TABLE carts DROP COLUMN abandoned_at;
+ALTER TABLE carts DROP COLUMN updated_at;
+ALTER TABLE carts DROP COLUMN created_at;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE carts ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open'
+    CHECK (status IN ('open', 'checking_out', 'ordered', 'cancelled'));
+
+CREATE TABLE orders (
+    id SERIAL PRIMARY KEY,
+    cart_id INT UNIQUE REFERENCES carts(id) ON DELETE SET NULL,
+    user_id VARCHAR(255),
+    session_id VARCHAR(255),
+    currency CHAR(3) NOT NULL,
+    total_price DECIMAL(10, 2) NOT NULL,
+    discount_amount DECIMAL(10, 2) NOT NULL,
+    final_price DECIMAL(10, 2) NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX orders_user_id_idx ON orders (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE orders;
+ALTER TABLE carts DROP COLUMN status;
+-- +goose StatementEnd
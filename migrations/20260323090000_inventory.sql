@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE inventory (
+    sku VARCHAR(64) PRIMARY KEY REFERENCES products(sku) ON DELETE CASCADE,
+    on_hand INT NOT NULL CHECK (on_hand >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE reservations (
+    cart_id INT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL REFERENCES inventory(sku) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (cart_id, sku)
+);
+CREATE INDEX reservations_sku_idx ON reservations (sku, expires_at);
+CREATE INDEX reservations_expires_at_idx ON reservations (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE reservations;
+DROP TABLE inventory;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products ADD COLUMN tax_category VARCHAR(32) NOT NULL DEFAULT 'standard';
+ALTER TABLE cart_item ADD COLUMN tax_category VARCHAR(32) NOT NULL DEFAULT 'standard';
+
+CREATE TABLE tax_rates (
+    region VARCHAR(16) NOT NULL,
+    category VARCHAR(32) NOT NULL,
+    rate NUMERIC(7, 4) NOT NULL CHECK (rate >= 0 AND rate < 100),
+    PRIMARY KEY (region, category)
+);
+
+ALTER TABLE orders ADD COLUMN tax_total DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN grand_total DECIMAL(10, 2);
+UPDATE orders SET grand_total = final_price;
+ALTER TABLE orders ALTER COLUMN grand_total SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN grand_total;
+ALTER TABLE orders DROP COLUMN tax_total;
+DROP TABLE tax_rates;
+ALTER TABLE cart_item DROP COLUMN tax_category;
+ALTER TABLE products DROP COLUMN tax_category;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products
+    ADD COLUMN weight_grams INT NOT NULL DEFAULT 0 CHECK (weight_grams >= 0),
+    ADD COLUMN length_mm INT NOT NULL DEFAULT 0 CHECK (length_mm >= 0),
+    ADD COLUMN width_mm INT NOT NULL DEFAULT 0 CHECK (width_mm >= 0),
+    ADD COLUMN height_mm INT NOT NULL DEFAULT 0 CHECK (height_mm >= 0);
+ALTER TABLE cart_item
+    ADD COLUMN weight_grams INT NOT NULL DEFAULT 0,
+    ADD COLUMN length_mm INT NOT NULL DEFAULT 0,
+    ADD COLUMN width_mm INT NOT NULL DEFAULT 0,
+    ADD COLUMN height_mm INT NOT NULL DEFAULT 0;
+ALTER TABLE carts ADD COLUMN shipping_method VARCHAR(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE carts DROP COLUMN shipping_method;
+ALTER TABLE cart_item DROP COLUMN weight_grams, DROP COLUMN length_mm, DROP COLUMN width_mm, DROP COLUMN height_mm;
+ALTER TABLE products DROP COLUMN weight_grams, DROP COLUMN length_mm, DROP COLUMN width_mm, DROP COLUMN height_mm;
+-- +goose StatementEnd
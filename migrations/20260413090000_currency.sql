@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE carts ADD COLUMN currency CHAR(3);
+UPDATE carts SET currency = (
+    SELECT ci.currency FROM cart_item ci WHERE ci.cart_id = carts.id ORDER BY ci.id LIMIT 1
+);
+
+CREATE TABLE exchange_rates (
+    base CHAR(3) NOT NULL,
+    quote CHAR(3) NOT NULL,
+    rate NUMERIC(20, 8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (base, quote),
+    CHECK (base <> quote)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE exchange_rates;
+ALTER TABLE carts DROP COLUMN currency;
+-- +goose StatementEnd
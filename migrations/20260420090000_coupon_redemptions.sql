@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Coupon uses are now counted at checkout. Give back the uses taken by
+-- coupons attached to carts that have not been ordered.
+UPDATE coupons SET used_count = GREATEST(used_count - (
+    SELECT count(*) FROM cart_coupons cc JOIN carts c ON c.id = cc.cart_id
+    WHERE cc.code = coupons.code AND c.status <> 'ordered'
+), 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE coupons SET used_count = used_count + (
+    SELECT count(*) FROM cart_coupons cc JOIN carts c ON c.id = cc.cart_id
+    WHERE cc.code = coupons.code AND c.status <> 'ordered'
+);
+-- +goose StatementEnd
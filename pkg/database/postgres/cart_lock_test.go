@@ -0,0 +1,80 @@
+package postgres_test
+
+import (
+	"cart-api/internal/auth"
+	"cart-api/internal/migrate"
+	"cart-api/internal/model"
+	"cart-api/internal/repository/Cart"
+	"cart-api/internal/services"
+	"cart-api/pkg/database/postgres"
+	"cart-api/pkg/money"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestCartLockConcurrentLimit adds items to one cart from many connections at
+// once. Only the FOR NO KEY UPDATE lock of CartRepo.LockCart keeps the item
+// count from racing past the limit.
+func TestCartLockConcurrentLimit(t *testing.T) {
+	const workers = 50
+	limit := services.DefaultLimits().MaxDistinctProducts
+	db, err := postgres.New(&postgres.Config{
+		Host:     "localhost",
+		Port:     "5432",
+		Username: "postgres",
+		Password: "12345",
+		Database: "postgres",
+	})
+	require.NoError(t, err, "Could not connect to postgres")
+	defer db.Close()
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: "lock-test"})
+	migrator, err := migrate.New(db, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(ctx))
+
+	repo := Cart.New(db)
+	service := services.NewCartService(repo)
+	cart, err := service.CreateCart(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.DeleteCart(context.Background(), cart.ID) })
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var succeeded, rejected int
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			item := model.CartItem{CartId: cart.ID, Product: fmt.Sprintf("product-%d", i), Price: money.MustParse("1.00", "USD"), Quantity: 1}
+			_, err := service.CreateItem(ctx, item)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, services.ErrReachCartLimit):
+				rejected++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, limit, succeeded)
+	assert.Equal(t, workers-limit, rejected)
+	stored, err := repo.GetCart(ctx, cart.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Items, limit)
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+)
+
+const tracerName = "cart-api/postgres"
+
+// TracedConn runs every query in its own client span carrying the SQL text.
+// Arguments are never recorded.
+type TracedConn struct {
+	conn sqlx.ExtContext
+}
+
+// Traced wraps conn so its queries are traced.
+func Traced(conn sqlx.ExtContext) TracedConn {
+	return TracedConn{conn}
+}
+
+// TracedRows ends the span of its query when closed, so the span covers
+// reading the rows and not only sending the query.
+type TracedRows struct {
+	*sqlx.Rows
+	span trace.Span
+}
+
+func (r *TracedRows) Close() error {
+	iterErr := r.Rows.Err()
+	err := r.Rows.Close()
+	if r.span != nil {
+		endQuery(r.span, errors.Join(iterErr, err))
+		r.span = nil
+	}
+	return err
+}
+
+// TracedRow ends the span of its query once scanned.
+type TracedRow struct {
+	*sqlx.Row
+	span trace.Span
+}
+
+func (r *TracedRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	endQuery(r.span, err)
+	return err
+}
+
+func (r *TracedRow) StructScan(dest any) error {
+	err := r.Row.StructScan(dest)
+	endQuery(r.span, err)
+	return err
+}
+
+func (c TracedConn) QueryxContext(ctx context.Context, query string, args ...any) (*TracedRows, error) {
+	ctx, span := startQuery(ctx, query)
+	rows, err := c.conn.QueryxContext(ctx, query, args...)
+	if err != nil {
+		endQuery(span, err)
+		return nil, err
+	}
+	return &TracedRows{Rows: rows, span: span}, nil
+}
+
+func (c TracedConn) QueryRowxContext(ctx context.Context, query string, args ...any) *TracedRow {
+	ctx, span := startQuery(ctx, query)
+	return &TracedRow{Row: c.conn.QueryRowxContext(ctx, query, args...), span: span}
+}
+
+func (c TracedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	res, err := c.conn.ExecContext(ctx, query, args...)
+	endQuery(span, err)
+	return res, err
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := queryOperation(query)
+	return otel.Tracer(tracerName).Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", query),
+		),
+	)
+}
+
+func endQuery(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// queryOperation is the leading keyword of query, e.g. SELECT or WITH.
+func queryOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingConn struct {
+	sqlx.ExtContext
+}
+
+func (failingConn) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, errors.New("deadlock detected")
+}
+
+func TestTraced(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	query := "UPDATE carts SET status = $2 WHERE id = $1"
+	_, err := Traced(failingConn{}).ExecContext(context.Background(), query, 1, "ordered")
+
+	assert.Error(t, err)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "postgres UPDATE", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.query.text", query))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+// rowsDriver answers every query with the ids 1 and 2.
+type rowsDriver struct{}
+
+func (rowsDriver) Open(string) (driver.Conn, error) { return rowsConn{}, nil }
+
+type rowsConn struct{ driver.Conn }
+
+func (rowsConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &idRows{}, nil
+}
+
+func (rowsConn) Close() error { return nil }
+
+type idRows struct{ next int64 }
+
+func (*idRows) Columns() []string { return []string{"id"} }
+func (*idRows) Close() error      { return nil }
+
+func (r *idRows) Next(dest []driver.Value) error {
+	if r.next == 2 {
+		return io.EOF
+	}
+	r.next++
+	dest[0] = r.next
+	return nil
+}
+
+func init() {
+	sql.Register("postgres-trace-test", rowsDriver{})
+}
+
+func TestTraced_SpanCoversRows(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	db := sqlx.MustOpen("postgres-trace-test", "")
+	t.Cleanup(func() { _ = db.Close() })
+	ctx := context.Background()
+
+	t.Run("Rows", func(t *testing.T) {
+		recorder.Reset()
+		rows, err := Traced(db).QueryxContext(ctx, "SELECT id FROM cart_item")
+		require.NoError(t, err)
+		for rows.Next() {
+			assert.Empty(t, recorder.Ended(), "span ended while reading rows")
+		}
+		require.NoError(t, rows.Close())
+		require.NoError(t, rows.Close())
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "postgres SELECT", spans[0].Name())
+	})
+
+	t.Run("Row", func(t *testing.T) {
+		recorder.Reset()
+		row := Traced(db).QueryRowxContext(ctx, "SELECT id FROM carts")
+		assert.Empty(t, recorder.Ended(), "span ended before the row was scanned")
+
+		var id int
+		require.NoError(t, row.Scan(&id))
+		assert.Equal(t, 1, id)
+		assert.Len(t, recorder.Ended(), 1)
+	})
+}
+
+func TestQueryOperation(t *testing.T) {
+	assert.Equal(t, "WITH", queryOperation("\n\twith touched AS (UPDATE carts ...) SELECT 1"))
+	assert.Equal(t, "SELECT", queryOperation("SELECT id FROM carts"))
+	assert.Equal(t, "QUERY", queryOperation("  "))
+}
@@ -1,9 +1,12 @@
 package logger
 
 import (
+	"context"
 	"go.uber.org/zap"
 )
 
+type ctxKey struct{}
+
 func New() (*zap.Logger, error) {
 	logger, err := zap.NewProduction()
 	if err != nil {
@@ -11,3 +14,16 @@ func New() (*zap.Logger, error) {
 	}
 	return logger, nil
 }
+
+// WithContext stores a request-scoped logger in ctx.
+func WithContext(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or fallback when there is none.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
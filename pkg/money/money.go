@@ -0,0 +1,367 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultCurrency = "USD"
+	// minorDigits is the number of decimals of most currencies. Amounts
+	// decoded or scanned before their currency is known use it, and it is
+	// the scale of the database columns amounts are stored in.
+	minorDigits = 2
+	// maxStored is the largest amount, in hundredths, the DECIMAL(10,2)
+	// columns hold.
+	maxStored = 9_999_999_999
+)
+
+// zeroDecimal and threeDecimal list the ISO 4217 currencies whose minor unit
+// is not a hundredth of the major unit.
+var (
+	zeroDecimal = map[string]bool{
+		"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true,
+		"KMF": true, "KRW": true, "PYG": true, "RWF": true, "UGX": true, "UYI": true,
+		"VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+	}
+	threeDecimal = map[string]bool{
+		"BHD": true, "IQD": true, "JOD": true, "KWD": true, "LYD": true, "OMR": true, "TND": true,
+	}
+)
+
+var (
+	ErrInvalidAmount   = errors.New("invalid money amount")
+	ErrInvalidCurrency = errors.New("invalid currency code")
+)
+
+type RoundingMode int
+
+const (
+	RoundHalfUp RoundingMode = iota
+	RoundHalfEven
+)
+
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "half_up":
+		return RoundHalfUp, nil
+	case "half_even", "bankers":
+		return RoundHalfEven, nil
+	}
+	return 0, fmt.Errorf("unknown rounding mode %q", s)
+}
+
+// Money is an amount in minor units of Currency: cents for USD, yen for JPY.
+// Without a currency the amount is in hundredths.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func Parse(s string, currency string) (Money, error) {
+	amount, err := parseMinor(s, MinorDigits(currency))
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func MustParse(s string, currency string) Money {
+	m, err := Parse(s, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MinorDigits is the number of decimals of currency.
+func MinorDigits(currency string) int {
+	switch {
+	case zeroDecimal[currency]:
+		return 0
+	case threeDecimal[currency]:
+		return 3
+	}
+	return minorDigits
+}
+
+// ValidCurrency reports whether code is a currency amounts can be kept in:
+// three upper-case letters and no more decimals than the database stores.
+func ValidCurrency(code string) bool {
+	if len(code) != 3 || MinorDigits(code) > minorDigits {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxAmount is the largest amount of currency the database can store.
+func MaxAmount(currency string) Money {
+	amount := int64(maxStored)
+	for range minorDigits - MinorDigits(currency) {
+		amount /= 10
+	}
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Add returns m + o. It panics if both amounts carry a currency and the
+// currencies differ; a zero Money without currency adopts that of o.
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: sameCurrency(m, o)}
+}
+
+// Sub returns m - o and panics on mismatched currencies like Add.
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: sameCurrency(m, o)}
+}
+
+func sameCurrency(m, o Money) string {
+	switch {
+	case m.Currency == o.Currency, o.Currency == "":
+		return m.Currency
+	case m.Currency == "":
+		return o.Currency
+	}
+	panic(fmt.Sprintf("money: mixing currencies %s and %s", m.Currency, o.Currency))
+}
+
+// Mul returns m × n. It panics if the product does not fit in an int64
+// rather than silently wrapping around.
+func (m Money) Mul(n int64) Money {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(n))
+	return Money{Amount: toInt64(product), Currency: m.Currency}
+}
+
+func (m Money) Percent(percent int64, mode RoundingMode) Money {
+	return m.Ratio(percent, 100, mode)
+}
+
+// Ratio returns m × num / den rounded with mode. The product is computed
+// without overflow; like Mul, it panics if the result does not fit.
+func (m Money) Ratio(num, den int64, mode RoundingMode) Money {
+	n := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num))
+	return Money{Amount: bigDivRound(n, big.NewInt(den), mode), Currency: m.Currency}
+}
+
+// Convert returns m in currency at rate/scale units of currency per unit of
+// m.Currency, rescaling between the decimals of both currencies. The
+// product is computed without overflow.
+func (m Money) Convert(currency string, rate, scale int64, mode RoundingMode) Money {
+	n := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(rate))
+	n.Mul(n, pow10(MinorDigits(currency)))
+	d := new(big.Int).Mul(big.NewInt(scale), pow10(MinorDigits(m.Currency)))
+	return Money{Amount: bigDivRound(n, d, mode), Currency: currency}
+}
+
+// In returns m, an amount in hundredths that was decoded or scanned before
+// its currency was known, in the minor units of currency. Currencies without
+// cents round to the nearest unit, half up; Fits reports whether they have to.
+func (m Money) In(currency string) Money {
+	shift := minorDigits - MinorDigits(currency)
+	if shift <= 0 {
+		return Money{Amount: m.Amount * int64(math.Pow10(-shift)), Currency: currency}
+	}
+	return Money{Amount: divRound(m.Amount, int64(math.Pow10(shift)), RoundHalfUp), Currency: currency}
+}
+
+// Fits reports whether m, an amount in hundredths, can be expressed in
+// currency without rounding.
+func (m Money) Fits(currency string) bool {
+	shift := minorDigits - MinorDigits(currency)
+	return shift <= 0 || m.Amount%int64(math.Pow10(shift)) == 0
+}
+
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.Amount < o.Amount:
+		return -1
+	case m.Amount > o.Amount:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) String() string {
+	return formatMinor(m.Amount, MinorDigits(m.Currency))
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		return nil
+	}
+	amount, err := parseMinor(s, MinorDigits(m.Currency))
+	if err != nil {
+		return err
+	}
+	m.Amount = amount
+	return nil
+}
+
+// UnmarshalText parses a decimal amount such as "100.00", keeping Currency.
+func (m *Money) UnmarshalText(text []byte) error {
+	amount, err := parseMinor(string(text), MinorDigits(m.Currency))
+	if err != nil {
+		return err
+	}
+	m.Amount = amount
+	return nil
+}
+
+// Scan reads a database amount. The columns keep two decimals whatever the
+// currency, so the result is in hundredths and without currency, even when m
+// had one; In converts it once the currency is known.
+func (m *Money) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		*m = Money{Amount: v * int64(math.Pow10(minorDigits))}
+		return nil
+	case nil:
+		*m = Money{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into money", src)
+	}
+	amount, err := parseMinor(s, minorDigits)
+	if err != nil {
+		return err
+	}
+	*m = Money{Amount: amount}
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func divRound(n, d int64, mode RoundingMode) int64 {
+	if d < 0 {
+		n, d = -n, -d
+	}
+	q, r := n/d, n%d
+	if r == 0 {
+		return q
+	}
+	sign := int64(1)
+	if n < 0 {
+		sign, r = -1, -r
+	}
+	switch {
+	case 2*r > d:
+		q += sign
+	case 2*r == d:
+		if mode == RoundHalfUp || q%2 != 0 {
+			q += sign
+		}
+	}
+	return q
+}
+
+func bigDivRound(n, d *big.Int, mode RoundingMode) int64 {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return toInt64(q)
+	}
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	switch twice.Cmp(new(big.Int).Abs(d)) {
+	case 1:
+		q.Add(q, big.NewInt(int64(n.Sign()*d.Sign())))
+	case 0:
+		if mode == RoundHalfUp || q.Bit(0) != 0 {
+			q.Add(q, big.NewInt(int64(n.Sign()*d.Sign())))
+		}
+	}
+	return toInt64(q)
+}
+
+func toInt64(n *big.Int) int64 {
+	if !n.IsInt64() {
+		panic(fmt.Sprintf("money: amount %s out of range", n))
+	}
+	return n.Int64()
+}
+
+func parseMinor(s string, digits int) (int64, error) {
+	input := strings.TrimSpace(s)
+	s, negative := strings.CutPrefix(input, "-")
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if !isDigits(whole) || len(frac) > digits || (hasFrac && !isDigits(frac)) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, input)
+	}
+	frac += strings.Repeat("0", digits-len(frac))
+	major, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, input)
+	}
+	minor, _ := strconv.ParseInt("0"+frac, 10, 64)
+	minorPerMajor := int64(math.Pow10(digits))
+	if major > (math.MaxInt64-minor)/minorPerMajor {
+		return 0, fmt.Errorf("%w: %q out of range", ErrInvalidAmount, input)
+	}
+	amount := major*minorPerMajor + minor
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits, so signs
+// and spaces inside an amount are rejected.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func formatMinor(amount int64, digits int) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	if digits == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+	minorPerMajor := int64(math.Pow10(digits))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/minorPerMajor, digits, amount%minorPerMajor)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
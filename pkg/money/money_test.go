@@ -0,0 +1,201 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  int64
+		expectErr bool
+	}{
+		{input: "2500.50", expected: 250050},
+		{input: "2500.5", expected: 250050},
+		{input: "12", expected: 1200},
+		{input: "0.07", expected: 7},
+		{input: "-3.10", expected: -310},
+		{input: "1.005", expectErr: true},
+		{input: "", expectErr: true},
+		{input: "abc", expectErr: true},
+		{input: ".50", expectErr: true},
+		{input: "1.-5", expectErr: true},
+		{input: "--1", expectErr: true},
+		{input: "-+1", expectErr: true},
+		{input: "+1", expectErr: true},
+		{input: "1.+5", expectErr: true},
+		{input: "1. 5", expectErr: true},
+		{input: "1.", expectErr: true},
+		{input: "92233720368547758.07", expected: 9223372036854775807},
+		{input: "92233720368547758.08", expectErr: true},
+		{input: "9223372036854775807", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m, err := Parse(tt.input, DefaultCurrency)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m.Amount)
+			assert.Equal(t, DefaultCurrency, m.Currency)
+		})
+	}
+}
+
+func TestRatioRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		num, den int64
+		mode     RoundingMode
+		expected int64
+	}{
+		{name: "half up rounds half away from zero", amount: 25, num: 1, den: 10, mode: RoundHalfUp, expected: 3},
+		{name: "half even rounds half to even down", amount: 25, num: 1, den: 10, mode: RoundHalfEven, expected: 2},
+		{name: "half even rounds half to even up", amount: 35, num: 1, den: 10, mode: RoundHalfEven, expected: 4},
+		{name: "below half rounds down", amount: 24, num: 1, den: 10, mode: RoundHalfUp, expected: 2},
+		{name: "above half rounds up", amount: 26, num: 1, den: 10, mode: RoundHalfEven, expected: 3},
+		{name: "negative half up", amount: -25, num: 1, den: 10, mode: RoundHalfUp, expected: -3},
+		{name: "negative half even", amount: -25, num: 1, den: 10, mode: RoundHalfEven, expected: -2},
+		{name: "exact", amount: 62000, num: 10, den: 100, mode: RoundHalfUp, expected: 6200},
+		{name: "no overflow", amount: 1 << 40, num: 1 << 30, den: 1 << 32, mode: RoundHalfUp, expected: 1 << 38},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.amount, DefaultCurrency).Ratio(tt.num, tt.den, tt.mode)
+			assert.Equal(t, tt.expected, got.Amount)
+		})
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("19.99", "EUR")
+	b := MustParse("0.01", "EUR")
+
+	assert.Equal(t, "20.00", a.Add(b).String())
+	assert.Equal(t, "19.98", a.Sub(b).String())
+	assert.Equal(t, "59.97", a.Mul(3).String())
+	assert.Equal(t, "1.00", a.Percent(5, RoundHalfUp).String())
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, "-0.01", New(-1, "EUR").String())
+
+	var total Money
+	assert.Equal(t, "EUR", total.Add(a).Currency)
+	assert.PanicsWithValue(t, "money: mixing currencies EUR and USD", func() { a.Add(New(1, "USD")) })
+	assert.Panics(t, func() { a.Sub(New(1, "USD")) })
+
+	huge := New(math.MaxInt64/2+1, "EUR")
+	assert.PanicsWithValue(t, "money: amount 9223372036854775808 out of range", func() { huge.Mul(2) })
+	assert.Panics(t, func() { huge.Ratio(3, 1, RoundHalfUp) })
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		rate     int64
+		mode     RoundingMode
+		expected int64
+	}{
+		{name: "plain", amount: 10000, rate: 92_000_000, mode: RoundHalfUp, expected: 9200},
+		{name: "half up", amount: 1, rate: 50_000_000, mode: RoundHalfUp, expected: 1},
+		{name: "half even", amount: 1, rate: 50_000_000, mode: RoundHalfEven, expected: 0},
+		{name: "negative", amount: -1, rate: 50_000_000, mode: RoundHalfUp, expected: -1},
+		{name: "no overflow", amount: 1 << 40, rate: 15_000_000_000, mode: RoundHalfUp, expected: 150 * (1 << 40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.amount, "USD").Convert("EUR", tt.rate, 100_000_000, tt.mode)
+			assert.Equal(t, tt.expected, got.Amount)
+			assert.Equal(t, "EUR", got.Currency)
+		})
+	}
+}
+
+func TestZeroDecimalCurrency(t *testing.T) {
+	yen, err := Parse("1500", "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), yen.Amount)
+	assert.Equal(t, "1500", yen.String())
+	_, err = Parse("1500.50", "JPY")
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	_, err = Parse("1500.", "JPY")
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	assert.Equal(t, "99999999", MaxAmount("JPY").String())
+	assert.Equal(t, "99999999.99", MaxAmount("USD").String())
+
+	assert.Equal(t, 0, MinorDigits("JPY"))
+	assert.Equal(t, 2, MinorDigits("EUR"))
+	assert.True(t, ValidCurrency("JPY"))
+	assert.False(t, ValidCurrency("KWD"), "the database keeps two decimals")
+
+	scanned := MustParse("1500.00", "")
+	assert.Equal(t, yen, scanned.In("JPY"))
+	assert.True(t, scanned.Fits("JPY"))
+	assert.False(t, MustParse("1500.50", "").Fits("JPY"))
+	assert.Equal(t, int64(1501), MustParse("1500.50", "").In("JPY").Amount)
+
+	assert.Equal(t, "10.00", yen.Convert("USD", 666_667, 100_000_000, RoundHalfUp).String())
+	assert.Equal(t, "1500", MustParse("10.00", "USD").Convert("JPY", 15_000_000_000, 100_000_000, RoundHalfUp).String())
+}
+
+func TestJSON(t *testing.T) {
+	var payload struct {
+		Price Money `json:"price"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 2500.50}`), &payload))
+	assert.Equal(t, int64(250050), payload.Price.Amount)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price": "0.10"}`), &payload))
+	assert.Equal(t, int64(10), payload.Price.Amount)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": 0.105}`), &payload))
+
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": 0.10}`, string(data))
+}
+
+func TestScan(t *testing.T) {
+	var m Money
+	require.NoError(t, m.Scan([]byte("1200.00")))
+	assert.Equal(t, int64(120000), m.Amount)
+
+	v, err := m.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "1200.00", v)
+
+	yen := MustParse("1500", "JPY")
+	v, err = yen.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "1500", v)
+	require.NoError(t, yen.Scan([]byte("1500.00")), "the column keeps two decimals for every currency")
+	assert.Equal(t, MustParse("1500", "JPY"), yen.In("JPY"))
+	require.NoError(t, yen.Scan(int64(1500)))
+	assert.Equal(t, MustParse("1500", "JPY"), yen.In("JPY"))
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	mode, err := ParseRoundingMode("half_even")
+	require.NoError(t, err)
+	assert.Equal(t, RoundHalfEven, mode)
+
+	mode, err = ParseRoundingMode("")
+	require.NoError(t, err)
+	assert.Equal(t, RoundHalfUp, mode)
+
+	_, err = ParseRoundingMode("ceiling")
+	assert.Error(t, err)
+}